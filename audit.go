@@ -0,0 +1,161 @@
+package main
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	auditDialTimeout   = 3 * time.Second
+	certExpiryWarning  = 30 * 24 * time.Hour
+	severityError      = "error"
+	severityWarning    = "warning"
+	severityNotChecked = "not checked"
+)
+
+// Finding is a problem auditVPN found in a connection profile.
+type Finding struct {
+	Severity string
+	Message  string
+}
+
+// openvpnCertKeys are the vpn.data keys of OpenVPN profiles that point to
+// certificate or key files.
+var openvpnCertKeys = []string{"ca", "cert", "key", "ta", "tls-crypt"}
+
+// auditVPN checks a profile without connecting: the server is set and
+// reachable, and certificate files exist and haven't expired. Profiles
+// without a server setting to check, such as WireGuard ones whose endpoints
+// are in their peers, are reported as not checked rather than ok.
+func auditVPN(vpnName string) []Finding {
+	settings, err := readVPNSettings(vpnName)
+	if err != nil {
+		return []Finding{{severityError, fmt.Sprintf("could not read settings: %s", firstLine(err))}}
+	}
+	if settings.GatewayKey == "" {
+		return []Finding{{severityNotChecked, "no server setting to check"}}
+	}
+	
+	var findings []Finding
+	if strings.TrimSpace(settings.Gateway) == "" {
+		findings = append(findings, Finding{severityError, "no server (" + settings.GatewayKey + ") configured"})
+	} else {
+		findings = append(findings, auditServer(settings)...)
+	}
+	if strings.HasSuffix(settings.ServiceType, "openvpn") {
+		for _, key := range openvpnCertKeys {
+			if path := settings.Data[key]; path != "" {
+				findings = append(findings, auditCertFile(key, path)...)
+			}
+		}
+	}
+	return findings
+}
+
+// auditServer resolves the first server of a profile and, where the protocol
+// runs over TCP, dials it.
+func auditServer(settings VPNSettings) []Finding {
+	remote := strings.TrimSpace(strings.Split(settings.Gateway, ",")[0])
+	remote = strings.TrimPrefix(strings.TrimPrefix(remote, "https://"), "http://")
+	remote = strings.Split(remote, "/")[0]
+	host, port := remote, ""
+	if h, p, err := net.SplitHostPort(remote); err == nil {
+		host, port = h, p
+	}
+	
+	if _, err := net.LookupHost(host); err != nil {
+		return []Finding{{severityError, fmt.Sprintf("server %s does not resolve", host)}}
+	}
+	
+	switch {
+		case strings.HasSuffix(settings.ServiceType, "openvpn"):
+			if settings.Data["proto-tcp"] != "yes" {
+				return nil
+			}
+			if port == "" {
+				port = settings.Data["port"]
+			}
+			if port == "" {
+				port = "1194"
+			}
+		case strings.HasSuffix(settings.ServiceType, "openconnect"):
+			if port == "" {
+				port = "443"
+			}
+		default:
+			return nil
+	}
+	if _, err := strconv.Atoi(port); err != nil {
+		return nil
+	}
+	
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, port), auditDialTimeout)
+	if err != nil {
+		return []Finding{{severityError, fmt.Sprintf("server %s:%s is unreachable", host, port)}}
+	}
+	conn.Close()
+	return nil
+}
+
+func auditCertFile(key string, path string) []Finding {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return []Finding{{severityError, fmt.Sprintf("%s file %s is missing", key, path)}}
+	}
+	if err != nil {
+		return []Finding{{severityWarning, fmt.Sprintf("%s file %s is not readable", key, path)}}
+	}
+	
+	var findings []Finding
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		switch remaining := time.Until(cert.NotAfter); {
+			case remaining <= 0:
+				findings = append(findings, Finding{severityError, fmt.Sprintf("%s certificate %s expired on %s", key, cert.Subject.CommonName, cert.NotAfter.Format("2006-01-02"))})
+			case remaining < certExpiryWarning:
+				findings = append(findings, Finding{severityWarning, fmt.Sprintf("%s certificate %s expires on %s", key, cert.Subject.CommonName, cert.NotAfter.Format("2006-01-02"))})
+		}
+	}
+	return findings
+}
+
+func formatAudit(vpns []string, findings map[string][]Finding) string {
+	var result strings.Builder
+	table := tabwriter.NewWriter(&result, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "NAME\tSEVERITY\tPROBLEM")
+	broken, unchecked := 0, 0
+	for _, vpn := range vpns {
+		switch {
+			case len(findings[vpn]) == 0:
+				fmt.Fprintf(table, "%s\tok\t\n", vpnLabel(vpn))
+				continue
+			case findings[vpn][0].Severity == severityNotChecked:
+				unchecked++
+			default:
+				broken++
+		}
+		for _, finding := range findings[vpn] {
+			fmt.Fprintf(table, "%s\t%s\t%s\n", vpnLabel(vpn), finding.Severity, finding.Message)
+		}
+	}
+	table.Flush()
+	result.WriteString(fmt.Sprintf("\n%d of %d profiles have problems", broken, len(vpns)))
+	if unchecked > 0 {
+		result.WriteString(fmt.Sprintf(", %d could not be checked", unchecked))
+	}
+	result.WriteString("\n")
+	return result.String()
+}
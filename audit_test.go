@@ -0,0 +1,21 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAuditMarksWireGuardNotChecked(t *testing.T) {
+	useFakeRunner(t, map[string]string{
+		"nmcli -t connection show wg0": "connection.id:wg0\nconnection.type:wireguard\n",
+	})
+	
+	findings := map[string][]Finding{"wg0": auditVPN("wg0")}
+	if len(findings["wg0"]) != 1 || findings["wg0"][0].Severity != severityNotChecked {
+		t.Fatalf("auditVPN(wg0) = %v, want it not checked", findings["wg0"])
+	}
+	output := formatAudit([]string{"wg0"}, findings)
+	if strings.Contains(output, "ok") || !strings.Contains(output, "0 of 1 profiles have problems, 1 could not be checked") {
+		t.Errorf("formatAudit reported:\n%s", output)
+	}
+}
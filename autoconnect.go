@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type AutoconnectInfo struct {
+	Name        string
+	Autoconnect bool
+	Priority    int
+}
+
+func getAutoconnectInfo() []AutoconnectInfo {
+	output, err := executeCommand("nmcli", "-t", "-f", "NAME,TYPE,AUTOCONNECT,AUTOCONNECT-PRIORITY", "connection", "show")
+	if err != nil {
+		return nil
+	}
+	lines := outputLines(output)
+	
+	var infos []AutoconnectInfo
+	for _, line := range lines {
+		fields := parseTerseLine(line)
+		if len(fields) < 4 || (fields[1] != "vpn" && fields[1] != "wireguard") {
+			continue
+		}
+		priority, _ := strconv.Atoi(fields[3])
+		infos = append(infos, AutoconnectInfo{
+			Name:        fields[0],
+			Autoconnect: fields[2] == "yes",
+			Priority:    priority,
+		})
+	}
+	
+	sort.SliceStable(infos, func(i, j int) bool {
+		if infos[i].Autoconnect != infos[j].Autoconnect {
+			return infos[i].Autoconnect
+		}
+		if infos[i].Priority != infos[j].Priority {
+			return infos[i].Priority > infos[j].Priority
+		}
+		return infos[i].Name < infos[j].Name
+	})
+	return infos
+}
+
+func autoconnectEnabled() map[string]bool {
+	enabled := map[string]bool{}
+	for _, info := range getAutoconnectInfo() {
+		if info.Autoconnect {
+			enabled[info.Name] = true
+		}
+	}
+	return enabled
+}
+
+func autoconnectMarker(enabled bool) string {
+	if enabled {
+		return " *"
+	}
+	return ""
+}
+
+func autoconnectOrder() string {
+	infos := getAutoconnectInfo()
+	if len(infos) == 0 {
+		return "No VPN connections found"
+	}
+	
+	var result strings.Builder
+	result.WriteString("Autoconnect order (highest priority first):\n")
+	for i, info := range infos {
+		autoconnect := "no"
+		if info.Autoconnect {
+			autoconnect = "yes"
+		}
+		result.WriteString(fmt.Sprintf("%d. %s (priority %d, autoconnect %s)\n", i+1, info.Name, info.Priority, autoconnect))
+	}
+	return result.String()
+}
+
+func validatePriority(value string) error {
+	prio, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return fmt.Errorf("priority must be a whole number")
+	}
+	if prio < -999 || prio > 999 {
+		return fmt.Errorf("priority must be between -999 and 999")
+	}
+	return nil
+}
+
+func setAutoconnectPriority(name string, prio int) (string, error) {
+	return executeCommand("nmcli", "connection", "modify", name, "connection.autoconnect-priority", strconv.Itoa(prio))
+}
+
+func setAutoconnect(name string, enabled bool) error {
+	value := "no"
+	if enabled {
+		value = "yes"
+	}
+	_, err := executeCommand("nmcli", "connection", "modify", name, "connection.autoconnect", value)
+	return err
+}
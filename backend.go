@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+type VPN struct {
+	Name   string `json:"name"`
+	UUID   string `json:"uuid,omitempty"`
+	Type   string `json:"type"`
+	Active bool   `json:"active"`
+}
+
+var (
+	vpnNamesMu      sync.Mutex
+	vpnNames        = map[string]string{}
+	connectionNames = map[string]string{}
+)
+
+// vpnIDs returns the identifier charmvpn passes to the backend for each of
+// vpns. nmcli allows several connections with the same name, so those are
+// addressed by UUID and displayed with a short UUID suffix.
+func vpnIDs(vpns []VPN) []string {
+	count := map[string]int{}
+	for _, vpn := range vpns {
+		count[vpn.Name]++
+	}
+	
+	vpnNamesMu.Lock()
+	defer vpnNamesMu.Unlock()
+	ids := make([]string, len(vpns))
+	for i, vpn := range vpns {
+		ids[i] = vpn.Name
+		if count[vpn.Name] > 1 && vpn.UUID != "" {
+			ids[i] = vpn.UUID
+			vpnNames[vpn.UUID] = fmt.Sprintf("%s (%.8s)", vpn.Name, vpn.UUID)
+			connectionNames[vpn.UUID] = vpn.Name
+		}
+	}
+	return ids
+}
+
+// vpnDisplayName returns the name to show for an identifier from vpnIDs.
+func vpnDisplayName(id string) string {
+	vpnNamesMu.Lock()
+	defer vpnNamesMu.Unlock()
+	if name, ok := vpnNames[id]; ok {
+		return name
+	}
+	return id
+}
+
+// configName returns the connection name behind an identifier from vpnIDs.
+// The config keys pass entries, tags, favorites and the like by name, so
+// connections sharing a name share them.
+func configName(id string) string {
+	vpnNamesMu.Lock()
+	defer vpnNamesMu.Unlock()
+	if name, ok := connectionNames[id]; ok {
+		return name
+	}
+	return id
+}
+
+// vpnListTTL is how long a listing is reused. Menus list connections several
+// times per action, and each nmcli call is noticeable on slow systems.
+const vpnListTTL = 2 * time.Second
+
+var vpnListCache struct {
+	sync.Mutex
+	vpns []VPN
+	at   time.Time
+}
+
+// cachedVPNList returns backend.List(), reusing a listing younger than
+// vpnListTTL.
+func cachedVPNList() ([]VPN, error) {
+	vpnListCache.Lock()
+	defer vpnListCache.Unlock()
+	if !vpnListCache.at.IsZero() && time.Since(vpnListCache.at) < vpnListTTL {
+		return vpnListCache.vpns, nil
+	}
+	vpns, err := backend.List()
+	if err != nil {
+		return nil, err
+	}
+	vpnListCache.vpns, vpnListCache.at = vpns, time.Now()
+	return vpns, nil
+}
+
+// invalidateCache drops the cached listing after connections were added,
+// removed, renamed, connected or disconnected.
+func invalidateCache() {
+	vpnListCache.Lock()
+	defer vpnListCache.Unlock()
+	vpnListCache.vpns, vpnListCache.at = nil, time.Time{}
+}
+
+func getVPNs() []VPN {
+	vpns, _ := cachedVPNList()
+	return vpns
+}
+
+// Backend is what charmvpn needs from the system to manage VPN connections.
+// Connect, Disconnect and Import return the tool's output for display.
+type Backend interface {
+	Name() string
+	List() ([]VPN, error)
+	Connect(name string) (string, error)
+	Disconnect(name string) (string, error)
+	Status() ([]VPNStatus, error)
+	Import(path string) (string, error)
+}
+
+// secretBackend is implemented by backends that can pass a password, and
+// optionally a one-time code, along with the connection attempt. A non-empty
+// device binds the connection to that network device.
+type secretBackend interface {
+	ConnectWithSecret(name string, secret string, otp string, device string) (string, error)
+}
+
+var backend Backend = nmcliBackend{}
+
+func selectBackend(name string) (Backend, error) {
+	switch name {
+		case "nmcli":
+			return nmcliBackend{}, nil
+		case "wg-quick":
+			return wgQuickBackend{dir: wgQuickDir}, nil
+		case "":
+			if _, err := exec.LookPath("nmcli"); err != nil {
+				if _, err := exec.LookPath("wg-quick"); err == nil {
+					return wgQuickBackend{dir: wgQuickDir}, nil
+				}
+			}
+			return nmcliBackend{}, nil
+	}
+	return nil, fmt.Errorf("unknown backend %q (expected nmcli or wg-quick)", name)
+}
+
+func usingNmcli() bool {
+	_, ok := backend.(nmcliBackend)
+	return ok
+}
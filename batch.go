@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+func runBatchCommand(command string, arg string) (string, error) {
+	switch command {
+		case "connect":
+			if arg == "" {
+				return "", fmt.Errorf("connect needs a VPN name")
+			}
+			return connectVPN(arg)
+		case "disconnect":
+			if arg == "" {
+				return disconnectVPN()
+			}
+			return disconnectVPNByName(arg, true)
+		case "status":
+			return vpnStatus("")
+		case "wait":
+			d, err := time.ParseDuration(arg)
+			if err != nil {
+				return "", fmt.Errorf("wait needs a duration like 10s: %w", err)
+			}
+			time.Sleep(d)
+			return "", nil
+	}
+	return "", fmt.Errorf("unknown command %q", command)
+}
+
+// runBatch executes the commands in path line by line. An "on-error stop" or
+// "on-error continue" line switches how later failures are handled; stopping
+// is the default.
+func runBatch(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	
+	stopOnError := true
+	failures := 0
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		
+		command, arg, _ := strings.Cut(line, " ")
+		command = strings.ToLower(command)
+		arg = strings.Trim(strings.TrimSpace(arg), `"'`)
+		
+		if command == "on-error" {
+			switch arg {
+				case "stop":
+					stopOnError = true
+				case "continue":
+					stopOnError = false
+				default:
+					return fmt.Errorf("line %d: on-error must be stop or continue", lineNo)
+			}
+			continue
+		}
+		
+		fmt.Printf("> %s\n", line)
+		output, err := runBatchCommand(command, arg)
+		if output != "" {
+			fmt.Println(strings.TrimRight(output, "\n"))
+		}
+		if err != nil {
+			failures++
+			if stopOnError {
+				return fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			fmt.Fprintf(os.Stderr, "line %d: %s (continuing)\n", lineNo, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if failures > 0 {
+		return fmt.Errorf("%d command(s) failed", failures)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type BenchmarkResult struct {
+	Name        string
+	ConnectTime time.Duration
+	Speed       SpeedResult
+	Err         error
+}
+
+func connectionState(vpnName string) string {
+	output, err := executeCommand("nmcli", "-g", "GENERAL.STATE", "connection", "show", vpnName)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(output)
+}
+
+func waitUntilActive(vpnName string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		if connectionState(vpnName) == "activated" {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s did not become active within %s", vpnName, timeout)
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+}
+
+func benchmarkVPN(vpnName string) BenchmarkResult {
+	result := BenchmarkResult{Name: vpnName}
+	start := time.Now()
+	if _, err := connectVPN(vpnName); err != nil {
+		result.Err = fmt.Errorf("connect failed: %w", err)
+		return result
+	}
+	if err := waitUntilActive(vpnName, connectTimeout(vpnName)); err != nil {
+		result.Err = err
+		disconnectVPNByName(vpnName, true)
+		return result
+	}
+	result.ConnectTime = time.Since(start)
+	
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	result.Speed, result.Err = speedTest(ctx)
+	disconnectVPNByName(vpnName, true)
+	return result
+}
+
+func benchmarkVPNs(vpns []string) []BenchmarkResult {
+	results := make([]BenchmarkResult, 0, len(vpns))
+	for _, vpn := range vpns {
+		fmt.Printf("Benchmarking %s...\n", vpn)
+		results = append(results, benchmarkVPN(vpn))
+	}
+	
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if (a.Err == nil) != (b.Err == nil) {
+			return a.Err == nil
+		}
+		if a.Speed.Throughput != b.Speed.Throughput {
+			return a.Speed.Throughput > b.Speed.Throughput
+		}
+		return a.Speed.Latency < b.Speed.Latency
+	})
+	return results
+}
+
+func formatBenchmark(results []BenchmarkResult) string {
+	var result strings.Builder
+	result.WriteString("Benchmark results (fastest first):\n")
+	for i, r := range results {
+		if r.Err != nil {
+			result.WriteString(fmt.Sprintf("%d. %s: failed (%s)\n", i+1, r.Name, r.Err))
+			continue
+		}
+		result.WriteString(fmt.Sprintf("%d. %s: connect %s, latency %s, download %s/s\n",
+			i+1, r.Name,
+			r.ConnectTime.Round(100*time.Millisecond),
+			r.Speed.Latency.Round(time.Millisecond),
+			formatBytes(r.Speed.Throughput)))
+	}
+	return result.String()
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// captivePortalURL answers 204 No Content unless a captive portal intercepts
+// the request.
+const captivePortalURL = "http://connectivitycheck.gstatic.com/generate_204"
+
+func captivePortalDetected(ctx context.Context) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, captivePortalURL, nil)
+	if err != nil {
+		return false, err
+	}
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode != http.StatusNoContent, nil
+}
+
+// confirmCaptivePortal warns when the network is behind a captive portal,
+// which makes VPN connects fail, and asks whether to connect anyway.
+func confirmCaptivePortal() bool {
+	if !config.CheckCaptivePortal {
+		return true
+	}
+	ctx, cancel := context.WithTimeout(baseContext(), 3*time.Second)
+	defer cancel()
+	detected, err := captivePortalDetected(ctx)
+	if err != nil {
+		logVerbose("captive portal check failed: %v", err)
+		return true
+	}
+	if !detected {
+		return true
+	}
+	fmt.Println(warningStyle.Render("This network seems to be behind a captive portal. Sign in through a browser first, or the VPN will likely fail to connect."))
+	return confirm("Connect anyway?", "")
+}
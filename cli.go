@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+)
+
+type cliOptions struct {
+	connect    string
+	disconnect bool
+	list       bool
+	status     bool
+	json       bool
+	quiet      bool
+	force      bool
+}
+
+// ErrUnknownVPN is returned when a VPN named on the command line doesn't exist.
+var ErrUnknownVPN = errors.New("unknown VPN")
+
+// ErrAmbiguousVPN is returned when a name on the command line matches several
+// VPNs.
+var ErrAmbiguousVPN = errors.New("ambiguous VPN name")
+
+// Exit codes of the non-interactive path, so scripts can tell failures apart.
+const (
+	exitFailure    = 1
+	exitUnknownVPN = 2
+	exitTimeout    = 3
+)
+
+func exitCode(err error) int {
+	switch {
+		case err == nil:
+			return 0
+		case errors.Is(err, ErrUnknownVPN), errors.Is(err, ErrAmbiguousVPN):
+			return exitUnknownVPN
+		case errors.Is(err, ErrTimeout):
+			return exitTimeout
+	}
+	return exitFailure
+}
+
+func (o cliOptions) requested() bool {
+	return o.connect != "" || o.disconnect || o.list || o.status
+}
+
+func marshalJSON(v any) (string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func listVPNsJSON() (string, error) {
+	vpns, err := backend.List()
+	if err != nil {
+		return "", err
+	}
+	if vpns == nil {
+		vpns = []VPN{}
+	}
+	return marshalJSON(vpns)
+}
+
+// statusEntry is an element of --status --json. connected_since, the name the
+// connect time had first, stays next to since for existing scripts.
+type statusEntry struct {
+	VPNStatus
+	ConnectedSince time.Time `json:"connected_since,omitzero"`
+}
+
+func statusJSON() (string, error) {
+	statuses, err := backend.Status()
+	if err != nil {
+		return "", err
+	}
+	entries := make([]statusEntry, len(statuses))
+	for i, status := range statuses {
+		if status.Since.IsZero() {
+			status.Since = recordedConnectedAt(status.Name)
+		}
+		entries[i] = statusEntry{VPNStatus: status, ConnectedSince: status.Since}
+	}
+	return marshalJSON(entries)
+}
+
+// resolveVPNName finds the VPN meant by query: an exact name or UUID, else the
+// only VPN whose name matches case-insensitively, else the only one containing
+// query. When the list can't be read query is returned as is, so the connect
+// attempt reports the actual problem.
+func resolveVPNName(query string) (string, error) {
+	vpns, err := cachedVPNList()
+	if err != nil {
+		return query, nil
+	}
+	ids := vpnIDs(vpns)
+	
+	var equal, contains []string
+	for i, vpn := range vpns {
+		switch {
+			case ids[i] == query || vpn.UUID == query:
+				return ids[i], nil
+			case strings.EqualFold(vpn.Name, query):
+				equal = append(equal, ids[i])
+			case strings.Contains(strings.ToLower(vpn.Name), strings.ToLower(query)):
+				contains = append(contains, ids[i])
+		}
+	}
+	
+	matches := equal
+	if len(matches) == 0 {
+		matches = contains
+	}
+	switch len(matches) {
+		case 0:
+			return "", fmt.Errorf("%w: %s", ErrUnknownVPN, query)
+		case 1:
+			return matches[0], nil
+	}
+	candidates := make([]string, len(matches))
+	for i, match := range matches {
+		candidates[i] = vpnDisplayName(match)
+	}
+	sort.Strings(candidates)
+	return "", fmt.Errorf("%w: %q matches %s", ErrAmbiguousVPN, query, strings.Join(candidates, ", "))
+}
+
+// runCLI performs the actions requested on the command line instead of
+// starting the TUI. Disconnecting happens before connecting so the two can be
+// combined to switch VPNs.
+func runCLI(opts cliOptions) error {
+	var errs []error
+	run := func(output string, err error) {
+		if output != "" && !opts.quiet {
+			fmt.Println(simulated(output))
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	
+	if opts.disconnect {
+		run(disconnectVPN())
+	}
+	if opts.connect != "" {
+		if vpnName, err := resolveVPNName(opts.connect); err != nil {
+			run("", err)
+		} else {
+			connectOpts := connectOptions{force: opts.force}
+			connect := func(vpnName string) (string, error) {
+				return connectVPNWithSecret(vpnName, connectOpts, nil)
+			}
+			if term.IsTerminal(os.Stdin.Fd()) {
+				connect = func(vpnName string) (string, error) {
+					return connectInteractiveWith(vpnName, connectOpts)
+				}
+			}
+			var result ConnectResult
+			var output string
+			if interruptible(func() {
+				result, output = timedConnect(vpnName, connect)
+			}) {
+				abortConnect(vpnName)
+			}
+			if opts.json {
+				output, err = marshalJSON(result)
+				run(output, errors.Join(err, result.Err))
+			} else {
+				run(strings.TrimSuffix(output+result.Summary(), "\n"), result.Err)
+			}
+		}
+	}
+	if opts.list {
+		if opts.json {
+			run(listVPNsJSON())
+		} else {
+			run(listVPNs())
+		}
+	}
+	if opts.status {
+		if opts.json {
+			run(statusJSON())
+		} else {
+			run(vpnStatus(""))
+		}
+	}
+	return errors.Join(errs...)
+}
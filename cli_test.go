@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestStatusJSONLeavesStateAlone(t *testing.T) {
+	useFakeRunner(t, map[string]string{
+		"nmcli -t -f NAME,UUID,TYPE,ACTIVE connection show":             "Work:6b1c9e0e-0002-4a4e-9a5c-000000000002:vpn:yes\n",
+		"nmcli -t connection show 6b1c9e0e-0002-4a4e-9a5c-000000000002": "connection.id:Work\nGENERAL.STATE:activated\n",
+	})
+	
+	output, err := statusJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(output, `since"`) {
+		t.Errorf("statusJSON() reported a connect time nobody recorded: %s", output)
+	}
+	path, err := statePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("statusJSON() wrote %s", path)
+	}
+}
+
+func TestStatusJSONKeepsConnectedSince(t *testing.T) {
+	useFakeRunner(t, map[string]string{
+		"nmcli -t -f NAME,UUID,TYPE,ACTIVE connection show":             "Work:6b1c9e0e-0002-4a4e-9a5c-000000000002:vpn:yes\n",
+		"nmcli -t connection show 6b1c9e0e-0002-4a4e-9a5c-000000000002": "connection.id:Work\nGENERAL.STATE:activated\n",
+	})
+	recordConnected("Work")
+	
+	output, err := statusJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{`"connected_since"`, `"since"`} {
+		if !strings.Contains(output, key) {
+			t.Errorf("statusJSON() has no %s: %s", key, output)
+		}
+	}
+}
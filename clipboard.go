@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const clipboardTimeout = 5 * time.Second
+
+// clipboardCommand picks a clipboard tool: wl-copy on Wayland, otherwise
+// xclip or xsel.
+func clipboardCommand() ([]string, error) {
+	candidates := [][]string{
+		{"xclip", "-selection", "clipboard"},
+		{"xsel", "--clipboard", "--input"},
+	}
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		candidates = append([][]string{{"wl-copy"}}, candidates...)
+	}
+	for _, candidate := range candidates {
+		if _, err := exec.LookPath(candidate[0]); err == nil {
+			return candidate, nil
+		}
+	}
+	return nil, errors.New("no clipboard tool found, install wl-clipboard, xclip or xsel")
+}
+
+func copyToClipboard(text string) error {
+	command, err := clipboardCommand()
+	if err != nil {
+		return err
+	}
+	// The tools fork to keep serving the selection, so they run without output
+	// pipes; waiting on those through the runner would block until the child
+	// exits.
+	ctx, cancel := context.WithTimeout(baseContext(), clipboardTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+type ReminderConfig struct {
+	After time.Duration `toml:"after"`
+	Every time.Duration `toml:"every"`
+}
+
+type Config struct {
+	ExportDir                   string                   `toml:"export_dir,omitempty"`
+	RestrictExports             bool                     `toml:"restrict_exports"`
+	ConfigDir                   string                   `toml:"config_dir,omitempty"`
+	Backend                     string                   `toml:"backend,omitempty"`
+	Theme                       string                   `toml:"theme,omitempty"`
+	Favorites                   []string                 `toml:"favorites,omitempty"`
+	LastConnected               string                   `toml:"last_connected,omitempty"`
+	ConnectTimeout              time.Duration            `toml:"connect_timeout"`
+	ConnectAttempts             int                      `toml:"connect_attempts"`
+	Timeouts                    map[string]time.Duration `toml:"timeouts,omitempty"`
+	SafetyDelay                 time.Duration            `toml:"safety_delay,omitempty"`
+	PingAfterConnect            bool                     `toml:"ping_after_connect"`
+	ShowPublicIP                bool                     `toml:"show_public_ip"`
+	CheckDNSLeak                bool                     `toml:"check_dns_leak"`
+	CheckIPv6Leak               bool                     `toml:"check_ipv6_leak"`
+	CheckCaptivePortal          bool                     `toml:"check_captive_portal"`
+	Notifications               bool                     `toml:"notifications"`
+	Countries                   map[string]string        `toml:"countries,omitempty"`
+	Providers                   map[string]string        `toml:"providers,omitempty"`
+	Tags                        map[string][]string      `toml:"tags,omitempty"`
+	ExpectedCountries           map[string]string        `toml:"expected_countries,omitempty"`
+	Pass                        map[string]string        `toml:"pass,omitempty"`
+	OTP                         map[string]string        `toml:"otp,omitempty"`
+	DisconnectOnCountryMismatch bool                     `toml:"disconnect_on_country_mismatch"`
+	Reminder                    ReminderConfig           `toml:"reminder"`
+	Hooks                       HooksConfig              `toml:"hooks"`
+	VPNHooks                    map[string]VPNHooks      `toml:"vpn_hooks,omitempty"`
+	WiFiVPNs                    map[string]string        `toml:"wifi_vpns,omitempty"`
+	AutoconnectOnWiFi           bool                     `toml:"autoconnect_on_wifi"`
+}
+
+// DefaultCommandTimeout bounds nmcli up/down/import calls unless the config
+// file overrides it with connect_timeout.
+const DefaultCommandTimeout = 30 * time.Second
+
+var config = defaultConfig()
+
+func defaultConfig() Config {
+	return Config{
+		ConnectTimeout:     DefaultCommandTimeout,
+		ConnectAttempts:    3,
+		PingAfterConnect:   true,
+		ShowPublicIP:       true,
+		CheckDNSLeak:       true,
+		CheckIPv6Leak:      true,
+		CheckCaptivePortal: true,
+		Notifications:      true,
+		Reminder: ReminderConfig{
+			Every: time.Hour,
+		},
+	}
+}
+
+func configPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "charmvpn", "config.toml"), nil
+}
+
+func loadConfig() (Config, error) {
+	cfg := defaultConfig()
+	path, err := configPath()
+	if err != nil {
+		return cfg, err
+	}
+	
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return defaultConfig(), nil
+		}
+		return defaultConfig(), err
+	}
+	return cfg, nil
+}
+
+// configErr is why the config file couldn't be loaded. saveConfig won't
+// replace such a file, so a typo doesn't cost the user their settings.
+var configErr error
+
+// saveConfig writes cfg to the config file. The file is rewritten as a whole,
+// so comments in it are lost.
+func saveConfig(cfg Config) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	if configErr != nil {
+		return fmt.Errorf("not overwriting %s, which could not be loaded: %w", path, configErr)
+	}
+	if dryRunSkips(path) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	
+	tmp, err := os.CreateTemp(filepath.Dir(path), "config-*.toml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	
+	if err := toml.NewEncoder(tmp).Encode(cfg); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// rememberLastConnected stores the VPN that was connected most recently in
+// the state file so the Connect menu can preselect it next time.
+func rememberLastConnected(vpnName string) {
+	updateState(func(s *State) {
+		s.LastConnected = vpnName
+	})
+}
+
+// lastConnectedVPN returns the last connected VPN if it is still in vpns.
+// last_connected in config files written by older versions is still honored
+// until the first connect.
+func lastConnectedVPN(vpns []string) string {
+	last := loadState().LastConnected
+	if last == "" {
+		last = config.LastConnected
+	}
+	for _, vpn := range vpns {
+		if vpn == last {
+			return vpn
+		}
+	}
+	return ""
+}
+
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
+
+// exportDir is where exports land when no path is given: export_dir from the
+// config, or the home directory.
+func exportDir() (string, error) {
+	if config.ExportDir != "" {
+		return expandHome(config.ExportDir)
+	}
+	return os.UserHomeDir()
+}
+
+// defaultConfigDir is scanned for profiles to import when config_dir is unset.
+const defaultConfigDir = "~/.vpn-configs"
+
+func configDir() (string, error) {
+	if config.ConfigDir != "" {
+		return expandHome(config.ConfigDir)
+	}
+	return expandHome(defaultConfigDir)
+}
+
+func connectTimeout(vpnName string) time.Duration {
+	if timeout, ok := config.Timeouts[configName(vpnName)]; ok && timeout > 0 {
+		return timeout
+	}
+	if config.ConnectTimeout > 0 {
+		return config.ConnectTimeout
+	}
+	return DefaultCommandTimeout
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveConfigKeepsUnreadableConfig(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path, err := configPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	broken := "favorites = [\"Work\"\n# a comment worth keeping\n"
+	if err := os.WriteFile(path, []byte(broken), 0600); err != nil {
+		t.Fatal(err)
+	}
+	
+	cfg, err := loadConfig()
+	if err == nil {
+		t.Fatal("loadConfig() succeeded on a broken file")
+	}
+	configErr = err
+	defer func() { configErr = nil }()
+	
+	if err := saveConfig(cfg); err == nil {
+		t.Error("saveConfig() overwrote a config that failed to load")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != broken {
+		t.Errorf("config file changed to %q", data)
+	}
+}
+
+func TestLastConnectedLivesInState(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	
+	rememberLastConnected("Work")
+	if got := lastConnectedVPN([]string{"Home", "Work"}); got != "Work" {
+		t.Errorf("lastConnectedVPN() = %q, want Work", got)
+	}
+	if got := lastConnectedVPN([]string{"Home"}); got != "" {
+		t.Errorf("lastConnectedVPN() = %q for a removed VPN, want none", got)
+	}
+	renameState("Work", "Office")
+	if got := lastConnectedVPN([]string{"Office"}); got != "Office" {
+		t.Errorf("lastConnectedVPN() after rename = %q, want Office", got)
+	}
+	if configExists() {
+		t.Error("remembering the last VPN wrote the config file")
+	}
+}
+
+func TestExpandHome(t *testing.T) {
+	t.Setenv("HOME", "/home/alice")
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"~", "/home/alice"},
+		{"~/", "/home/alice"},
+		{"~/x", "/home/alice/x"},
+		{"~/vpn/configs", "/home/alice/vpn/configs"},
+		{"~bob/x", "~bob/x"},
+		{"/etc/wireguard", "/etc/wireguard"},
+		{"configs/~/x", "configs/~/x"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got, err := expandHome(tt.path)
+		if err != nil {
+			t.Fatalf("expandHome(%q): %v", tt.path, err)
+		}
+		if got != tt.want {
+			t.Errorf("expandHome(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
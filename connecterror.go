@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ConnectError is a connect failure nmcli gave a known reason for. Raw keeps
+// nmcli's own output for the log.
+type ConnectError struct {
+	Reason string
+	Raw    string
+	Err    error
+}
+
+type connectFailure struct {
+	messages []string
+	reason   string
+	advice   string
+}
+
+// connectFailures maps nmcli's failure messages, matched case-insensitively,
+// to what the user can do about them.
+var connectFailures = []connectFailure{
+	{
+		messages: []string{"no valid vpn secrets", "secrets were required, but not provided"},
+		reason:   "no password or other secrets were provided",
+		advice:   "connect from a terminal to be asked for them, or configure a pass entry",
+	},
+	{
+		messages: []string{"login failed", "authentication failed", "auth_failed"},
+		reason:   "the server rejected the login",
+		advice:   "check the username and password, and use \"Forget saved password\" if a stale one is saved",
+	},
+	{
+		messages: []string{"vpn service failed to start"},
+		reason:   "the VPN plugin failed to start",
+		advice:   "make sure the NetworkManager plugin for this VPN type is installed, e.g. network-manager-openvpn",
+	},
+	{
+		messages: []string{"vpn service stopped unexpectedly"},
+		reason:   "the VPN plugin stopped unexpectedly",
+		advice:   "see `journalctl -u NetworkManager` for the cause",
+	},
+	{
+		messages: []string{"vpn service returned invalid configuration"},
+		reason:   "the VPN returned an invalid configuration",
+		advice:   "run \"Check VPN profiles\" and review the connection with \"Edit VPN\"",
+	},
+	{
+		messages: []string{"connection attempt to the vpn service timed out", "vpn service did not start in time"},
+		reason:   "the VPN server did not respond",
+		advice:   "check the server address and that this network allows VPN traffic",
+	},
+	{
+		messages: []string{"base network connection was interrupted"},
+		reason:   "the network connection dropped",
+		advice:   "reconnect to the network and try again",
+	},
+	{
+		messages: []string{"ip configuration could not be reserved", "ip configuration was unavailable"},
+		reason:   "no IP address was assigned",
+		advice:   "try again, or check the IPv4 settings of the connection",
+	},
+	{
+		messages: []string{"not authorized to control networking", "insufficient privileges"},
+		reason:   "you are not allowed to manage network connections",
+		advice:   "check the polkit rules for NetworkManager or ask an administrator",
+	},
+	{
+		messages: []string{"unknown connection"},
+		reason:   "NetworkManager doesn't know this connection",
+		advice:   "it may have been removed outside charmvpn",
+	},
+}
+
+func (e *ConnectError) Error() string {
+	for _, failure := range connectFailures {
+		if failure.reason == e.Reason {
+			return fmt.Sprintf("%s: %s", e.Reason, failure.advice)
+		}
+	}
+	return e.Reason
+}
+
+func (e *ConnectError) Unwrap() error {
+	return e.Err
+}
+
+// parseConnectError turns a failed nmcli connect into a *ConnectError when
+// its output names a known reason, and returns err unchanged otherwise.
+func parseConnectError(output string, err error) error {
+	if err == nil || errors.Is(err, ErrTimeout) {
+		return err
+	}
+	raw := strings.TrimSpace(output + "\n" + err.Error())
+	lower := strings.ToLower(raw)
+	for _, failure := range connectFailures {
+		for _, msg := range failure.messages {
+			if strings.Contains(lower, msg) {
+				logVerbose("nmcli: %s", raw)
+				return &ConnectError{Reason: failure.reason, Raw: raw, Err: err}
+			}
+		}
+	}
+	return err
+}
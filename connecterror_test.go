@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParseConnectError(t *testing.T) {
+	exitStatus := errors.New("exit status 4")
+	tests := []struct {
+		name   string
+		output string
+		err    error
+		reason string
+	}{
+		{
+			name:   "missing secrets",
+			output: "Error: Connection activation failed: No valid secrets\nHint: use 'journalctl -xe NM_CONNECTION=...' to get more details.\nError: Connection activation failed: no valid VPN secrets",
+			err:    exitStatus,
+			reason: "no password or other secrets were provided",
+		},
+		{
+			name:   "secrets required",
+			output: "Error: Connection activation failed: Secrets were required, but not provided",
+			err:    exitStatus,
+			reason: "no password or other secrets were provided",
+		},
+		{
+			name:   "login failed",
+			output: "Error: Connection activation failed: The VPN service returned invalid configuration\nAUTH_FAILED",
+			err:    exitStatus,
+			reason: "the server rejected the login",
+		},
+		{
+			name:   "missing plugin",
+			output: "Error: Connection activation failed: The VPN service failed to start",
+			err:    exitStatus,
+			reason: "the VPN plugin failed to start",
+		},
+		{
+			name:   "server timeout",
+			output: "Error: Connection activation failed: The connection attempt to the VPN service timed out",
+			err:    exitStatus,
+			reason: "the VPN server did not respond",
+		},
+		{
+			name:   "no privileges",
+			output: "Error: Connection activation failed: Not authorized to control networking.",
+			err:    exitStatus,
+			reason: "you are not allowed to manage network connections",
+		},
+		{
+			name:   "unknown connection",
+			output: "Error: unknown connection 'Work'.",
+			err:    exitStatus,
+			reason: "NetworkManager doesn't know this connection",
+		},
+		{
+			name:   "unrecognized failure",
+			output: "Error: Connection activation failed: something new",
+			err:    exitStatus,
+		},
+		{
+			name:   "success",
+			output: "Connection successfully activated (D-Bus active path: /org/freedesktop/NetworkManager/ActiveConnection/7)",
+		},
+		{
+			name:   "timeout",
+			output: "Error: Connection activation failed: no valid VPN secrets",
+			err:    fmt.Errorf("connection: %w", ErrTimeout),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parseConnectError(tt.output, tt.err)
+			var connectErr *ConnectError
+			if !errors.As(err, &connectErr) {
+				if tt.reason != "" {
+					t.Fatalf("parseConnectError() = %v, want reason %q", err, tt.reason)
+				}
+				if err != tt.err {
+					t.Errorf("parseConnectError() = %v, want %v unchanged", err, tt.err)
+				}
+				return
+			}
+			if connectErr.Reason != tt.reason {
+				t.Errorf("reason = %q, want %q", connectErr.Reason, tt.reason)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("%v does not wrap %v", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ConnectResult describes a connection attempt for monitoring: whether it
+// worked, how long it took and the address the VPN assigned.
+type ConnectResult struct {
+	Name    string
+	OK      bool
+	Elapsed time.Duration
+	IP4     string
+	Gateway string
+	Err     error
+	
+	// already is set when the VPN was up before, so nothing was done.
+	already bool
+}
+
+func (r ConnectResult) MarshalJSON() ([]byte, error) {
+	result := struct {
+		Name    string  `json:"name"`
+		OK      bool    `json:"ok"`
+		Elapsed float64 `json:"elapsed_seconds"`
+		IP4     string  `json:"ip4,omitempty"`
+		Gateway string  `json:"gateway,omitempty"`
+		Error   string  `json:"error,omitempty"`
+	}{
+		Name:    r.Name,
+		OK:      r.OK,
+		Elapsed: r.Elapsed.Round(time.Millisecond).Seconds(),
+		IP4:     r.IP4,
+		Gateway: r.Gateway,
+	}
+	if r.Err != nil {
+		result.Error = r.Err.Error()
+	}
+	return json.Marshal(result)
+}
+
+// Summary is the one-line description of a successful connect shown to the
+// user. Failures are reported through Err instead.
+func (r ConnectResult) Summary() string {
+	if !r.OK || r.already {
+		return ""
+	}
+	summary := fmt.Sprintf("Connected %s in %s", r.Name, r.Elapsed.Round(100*time.Millisecond))
+	if r.IP4 != "" {
+		summary += ", address " + r.IP4
+	}
+	if r.Gateway != "" {
+		summary += " via " + r.Gateway
+	}
+	return summary + "\n"
+}
+
+// connectionStatus looks up the status of a single active connection.
+func connectionStatus(vpnName string) (VPNStatus, bool) {
+	if usingNmcli() {
+		output, err := executeCommand("nmcli", "-t", "connection", "show", vpnName)
+		if err != nil {
+			return VPNStatus{}, false
+		}
+		status := parseConnectionShow(output)
+		return status, status.State != ""
+	}
+	statuses, _ := backend.Status()
+	for _, status := range statuses {
+		if status.Name == vpnName {
+			return status, true
+		}
+	}
+	return VPNStatus{}, false
+}
+
+// timedConnect runs connect for vpnName and describes the outcome in a
+// ConnectResult, along with connect's own output.
+func timedConnect(vpnName string, connect func(vpnName string) (string, error)) (ConnectResult, string) {
+	start := time.Now()
+	output, err := connect(vpnName)
+	already := errors.Is(err, ErrAlreadyConnected)
+	if already {
+		err = nil
+	}
+	result := ConnectResult{
+		Name:    vpnDisplayName(vpnName),
+		OK:      err == nil,
+		Elapsed: time.Since(start),
+		Err:     err,
+		already: already,
+	}
+	if err == nil {
+		if status, ok := connectionStatus(vpnName); ok {
+			result.IP4, result.Gateway = status.IP4, status.Gateway
+		}
+	}
+	return result, output
+}
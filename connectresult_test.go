@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConnectVPNAlreadyConnected(t *testing.T) {
+	fake := useFakeRunner(t, map[string]string{
+		"nmcli -t -f NAME,UUID,TYPE,ACTIVE connection show": "Work:6b1c9e0e-0002-4a4e-9a5c-000000000002:vpn:yes\n",
+	})
+	
+	output, err := connectVPNWithSecret("Work", connectOptions{}, nil)
+	if !errors.Is(err, ErrAlreadyConnected) {
+		t.Fatalf("connectVPNWithSecret() error = %v, want ErrAlreadyConnected", err)
+	}
+	if output != "Work is already connected" {
+		t.Errorf("connectVPNWithSecret() = %q", output)
+	}
+	if _, err := connectVPN("Work"); err != nil {
+		t.Errorf("connectVPN() = %v, want success", err)
+	}
+	if len(fake.commands) != 1 {
+		t.Errorf("ran %+v, want only the listing", fake.commands)
+	}
+}
+
+func TestTimedConnectAlreadyConnected(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		err     error
+		ok      bool
+		already bool
+	}{
+		{"already connected", "Work is already connected", ErrAlreadyConnected, true, true},
+		{"output that only looks like it", "Work is already connected", nil, true, false},
+		{"failure", "", errors.New("exit status 4"), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useFakeRunner(t, nil)
+			result, output := timedConnect("Work", func(string) (string, error) {
+				return tt.output, tt.err
+			})
+			if result.OK != tt.ok || result.already != tt.already {
+				t.Errorf("timedConnect() = ok %v, already %v, want %v, %v", result.OK, result.already, tt.ok, tt.already)
+			}
+			if output != tt.output {
+				t.Errorf("timedConnect() output = %q, want %q", output, tt.output)
+			}
+		})
+	}
+}
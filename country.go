@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var warningStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("196"))
+
+var plain bool
+
+func flagEmoji(country string) string {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if len(country) != 2 || country[0] < 'A' || country[0] > 'Z' || country[1] < 'A' || country[1] > 'Z' {
+		return ""
+	}
+	const regionalIndicatorA = 0x1F1E6
+	return string([]rune{
+		rune(regionalIndicatorA + int(country[0]-'A')),
+		rune(regionalIndicatorA + int(country[1]-'A')),
+	})
+}
+
+func vpnCountry(vpnName string) string {
+	if country := config.Countries[configName(vpnName)]; country != "" {
+		return country
+	}
+	if country := config.ExpectedCountries[configName(vpnName)]; country != "" {
+		return country
+	}
+	return loadState().Countries[vpnName]
+}
+
+func vpnLabel(vpnName string) string {
+	label := vpnDisplayName(vpnName)
+	if !plain {
+		if emoji := flagEmoji(vpnCountry(vpnName)); emoji != "" {
+			label = emoji + " " + label
+		}
+	}
+	if isFavorite(vpnName) {
+		label = "★ " + label
+	}
+	return label
+}
+
+func vpnOption(vpnName string) huh.Option[string] {
+	return huh.NewOption(vpnLabel(vpnName), vpnName)
+}
+
+func detectExitCountry(vpnName string) {
+	if !config.ShowPublicIP || vpnCountry(vpnName) != "" {
+		return
+	}
+	
+	ctx, cancel := context.WithTimeout(baseContext(), 3*time.Second)
+	defer cancel()
+	info, err := fetchPublicIP(ctx)
+	if err != nil || info.Country == "" {
+		return
+	}
+	updateState(func(s *State) {
+		s.Countries[vpnName] = strings.ToUpper(info.Country)
+	})
+}
+
+func expectedCountry(vpnName string) string {
+	return strings.ToUpper(strings.TrimSpace(config.ExpectedCountries[configName(vpnName)]))
+}
+
+// verifyExitCountry checks the exit country of a freshly connected VPN against
+// the configured expectation. It returns a warning to show the user, and
+// whether the connection should be considered failed.
+func verifyExitCountry(vpnName string) (string, bool) {
+	expected := expectedCountry(vpnName)
+	if expected == "" {
+		return "", true
+	}
+	
+	ctx, cancel := context.WithTimeout(baseContext(), 5*time.Second)
+	defer cancel()
+	info, err := fetchPublicIP(ctx)
+	if err != nil {
+		return fmt.Sprintf("Warning: could not verify exit country for %s: %s\n", vpnName, err), true
+	}
+	if strings.EqualFold(info.Country, expected) {
+		return fmt.Sprintf("Exit country verified: %s (%s)\n", info.Country, info.IP), true
+	}
+	
+	warning := warningStyle.Render(fmt.Sprintf("WARNING: %s is exiting in %s (%s), expected %s!", vpnName, info.Country, info.IP, expected))
+	if !config.DisconnectOnCountryMismatch {
+		return warning + "\n", true
+	}
+	if _, err := disconnectVPNByName(vpnName, true); err != nil {
+		return warning + "\n" + fmt.Sprintf("Could not disconnect %s: %s\n", vpnName, err), false
+	}
+	return warning + "\n" + fmt.Sprintf("Disconnected %s\n", vpnName), false
+}
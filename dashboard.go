@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const dashboardRefreshInterval = 3 * time.Second
+
+var (
+	dashboardTitleStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("63"))
+	dashboardActiveStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	dashboardInactiveStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	dashboardCursorStyle   = lipgloss.NewStyle().Bold(true)
+	dashboardHelpStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+)
+
+type dashboardRow struct {
+	Name   string
+	Active bool
+	IP4    string
+	Device string
+	Rx, Tx uint64
+	RxRate float64
+	TxRate float64
+}
+
+type dashboardRowsMsg struct {
+	rows []dashboardRow
+	at   time.Time
+}
+
+type dashboardTickMsg struct{}
+
+type dashboardResultMsg string
+
+type dashboardModel struct {
+	rows    []dashboardRow
+	at      time.Time
+	cursor  int
+	busy    string
+	message string
+}
+
+// loadDashboardRows combines the VPN list, the status of the active VPNs and
+// their interface counters into one row per VPN.
+func loadDashboardRows() tea.Msg {
+	active := map[string]bool{}
+	for _, vpn := range getActiveVPNs() {
+		active[vpn] = true
+	}
+	statuses := map[string]VPNStatus{}
+	if list, err := backend.Status(); err == nil {
+		for _, status := range list {
+			statuses[status.Name] = status
+		}
+	}
+	
+	msg := dashboardRowsMsg{at: time.Now()}
+	for _, vpn := range getVPNList() {
+		row := dashboardRow{Name: vpn, Active: active[vpn]}
+		if status, ok := statuses[vpn]; ok && row.Active {
+			row.IP4, row.Device = status.IP4, status.Device
+			row.Rx, row.Tx, _ = readInterfaceStats(row.Device)
+		}
+		msg.rows = append(msg.rows, row)
+	}
+	return msg
+}
+
+// withRates fills in the throughput of rows from the counters seen at the
+// previous refresh.
+func (m dashboardModel) withRates(msg dashboardRowsMsg) []dashboardRow {
+	previous := map[string]dashboardRow{}
+	for _, row := range m.rows {
+		previous[row.Name] = row
+	}
+	seconds := msg.at.Sub(m.at).Seconds()
+	for i, row := range msg.rows {
+		prev, ok := previous[row.Name]
+		if !ok || seconds <= 0 || row.Device == "" || prev.Device != row.Device || row.Rx < prev.Rx || row.Tx < prev.Tx {
+			continue
+		}
+		msg.rows[i].RxRate = float64(row.Rx-prev.Rx) / seconds
+		msg.rows[i].TxRate = float64(row.Tx-prev.Tx) / seconds
+	}
+	return msg.rows
+}
+
+func dashboardTick() tea.Cmd {
+	return tea.Tick(dashboardRefreshInterval, func(time.Time) tea.Msg {
+		return dashboardTickMsg{}
+	})
+}
+
+func dashboardCommand(verb string, vpnName string) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if verb == "connect" {
+			_, err = connectVPN(vpnName)
+		} else {
+			_, err = disconnectVPNByName(vpnName, false)
+		}
+		
+		if err != nil {
+			lines := strings.Split(strings.TrimSpace(err.Error()), "\n")
+			return dashboardResultMsg(fmt.Sprintf("Failed to %s %s: %s", verb, vpnName, lines[len(lines)-1]))
+		}
+		return dashboardResultMsg(fmt.Sprintf("%s: %sed", vpnName, verb))
+	}
+}
+
+func (m dashboardModel) Init() tea.Cmd {
+	return tea.Batch(loadDashboardRows, dashboardTick())
+}
+
+func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+		case dashboardRowsMsg:
+			m.rows, m.at = m.withRates(msg), msg.at
+			if m.cursor >= len(m.rows) {
+				m.cursor = max(len(m.rows)-1, 0)
+			}
+			return m, nil
+			
+		case dashboardTickMsg:
+			return m, tea.Batch(loadDashboardRows, dashboardTick())
+			
+		case dashboardResultMsg:
+			m.busy = ""
+			m.message = string(msg)
+			return m, loadDashboardRows
+			
+		case tea.KeyMsg:
+			switch msg.String() {
+				case "q", "esc", "ctrl+c":
+					return m, tea.Quit
+				case "j", "down":
+					if m.cursor < len(m.rows)-1 {
+						m.cursor++
+					}
+				case "k", "up":
+					if m.cursor > 0 {
+						m.cursor--
+					}
+				case "r":
+					return m, loadDashboardRows
+				case "c", "d", "enter", " ":
+					if m.busy != "" || len(m.rows) == 0 {
+						return m, nil
+					}
+					row := m.rows[m.cursor]
+					verb := "connect"
+					if msg.String() == "d" || (msg.String() != "c" && row.Active) {
+						verb = "disconnect"
+					}
+					if (verb == "connect") == row.Active {
+						m.message = fmt.Sprintf("%s is already %sed", row.Name, verb)
+						return m, nil
+					}
+					m.busy = fmt.Sprintf("%sing %s...", verb, row.Name)
+					m.message = ""
+					return m, dashboardCommand(verb, row.Name)
+			}
+	}
+	return m, nil
+}
+
+func (m dashboardModel) View() string {
+	var b strings.Builder
+	b.WriteString(dashboardTitleStyle.Render("charmvpn dashboard") + "\n\n")
+	
+	if len(m.rows) == 0 {
+		b.WriteString("No VPN connections found\n")
+	}
+	for i, row := range m.rows {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = dashboardCursorStyle.Render("> ")
+		}
+		state := dashboardInactiveStyle.Render("○ disconnected")
+		if row.Active {
+			state = dashboardActiveStyle.Render("● connected   ")
+		}
+		b.WriteString(fmt.Sprintf("%s%s  %s\n", cursor, state, vpnLabel(row.Name)))
+		if row.Active && row.Device != "" {
+			details := fmt.Sprintf("%s  %s  ↓ %s/s  ↑ %s/s", row.Device, row.IP4, formatBytes(row.RxRate), formatBytes(row.TxRate))
+			b.WriteString(strings.Repeat(" ", 18) + dashboardHelpStyle.Render(details) + "\n")
+		}
+	}
+	
+	b.WriteString("\n")
+	if m.busy != "" {
+		b.WriteString(m.busy + "\n")
+	} else if m.message != "" {
+		b.WriteString(m.message + "\n")
+	}
+	b.WriteString(dashboardHelpStyle.Render("j/k move • enter toggle • c connect • d disconnect • r refresh • q back") + "\n")
+	return b.String()
+}
+
+func runDashboard() error {
+	_, err := tea.NewProgram(dashboardModel{}).Run()
+	return err
+}
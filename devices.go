@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+)
+
+type Device struct {
+	Name       string
+	Type       string
+	State      string
+	Connection string
+}
+
+// virtualDeviceTypes can't carry a VPN: loopback and other tunnels.
+var virtualDeviceTypes = map[string]bool{
+	"loopback":  true,
+	"tun":       true,
+	"wireguard": true,
+	"bridge":    true,
+	"dummy":     true,
+}
+
+func listDevices() ([]Device, error) {
+	output, err := executeCommand("nmcli", "-t", "-f", "DEVICE,TYPE,STATE,CONNECTION", "device", "status")
+	if err != nil {
+		return nil, err
+	}
+	
+	var devices []Device
+	for _, line := range outputLines(output) {
+		fields := parseTerseLine(line)
+		if len(fields) < 4 || fields[0] == "" {
+			continue
+		}
+		devices = append(devices, Device{Name: fields[0], Type: fields[1], State: fields[2], Connection: fields[3]})
+	}
+	return devices, nil
+}
+
+// connectableDevices returns the connected devices a VPN can be bound to.
+func connectableDevices() []Device {
+	devices, err := listDevices()
+	if err != nil {
+		logVerbose("could not list devices: %v", err)
+		return nil
+	}
+	var connectable []Device
+	for _, device := range devices {
+		if strings.HasPrefix(device.State, "connected") && !virtualDeviceTypes[device.Type] {
+			connectable = append(connectable, device)
+		}
+	}
+	return connectable
+}
+
+// selectDevice offers to bind a connection to one device on machines with
+// several connected ones. An empty device leaves the choice to NetworkManager;
+// ok is false when the user backed out.
+func selectDevice() (device string, ok bool) {
+	if !usingNmcli() {
+		return "", true
+	}
+	devices := connectableDevices()
+	if len(devices) < 2 {
+		return "", true
+	}
+	
+	options := []huh.Option[string]{huh.NewOption("Default (let NetworkManager choose)", "")}
+	for _, d := range devices {
+		options = append(options, huh.NewOption(fmt.Sprintf("%s (%s, %s)", d.Name, d.Type, d.Connection), d.Name))
+	}
+	form := newForm(
+		newGroup(
+			huh.NewSelect[string]().
+				Title("Connect through device").
+				Value(&device).
+				Options(options...),
+		),
+	)
+	if err := form.Run(); err != nil {
+		return "", false
+	}
+	return device, true
+}
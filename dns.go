@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+type DNSSettings struct {
+	IPv4           []string
+	IPv6           []string
+	IgnoreAutoIPv4 bool
+}
+
+func splitServers(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
+
+func currentDNS(vpnName string) (DNSSettings, error) {
+	var settings DNSSettings
+	output, err := executeCommand("nmcli", "-t", "-f", "ipv4.dns,ipv4.ignore-auto-dns,ipv6.dns", "connection", "show", vpnName)
+	if err != nil {
+		return settings, err
+	}
+	for _, line := range outputLines(output) {
+		key, value, ok := terseProperty(line)
+		if !ok {
+			continue
+		}
+		switch key {
+			case "ipv4.dns":
+				settings.IPv4 = splitServers(value)
+			case "ipv4.ignore-auto-dns":
+				settings.IgnoreAutoIPv4 = value == "yes"
+			case "ipv6.dns":
+				settings.IPv6 = splitServers(value)
+		}
+	}
+	return settings, nil
+}
+
+func (s DNSSettings) String() string {
+	servers := append(append([]string(nil), s.IPv4...), s.IPv6...)
+	if len(servers) == 0 {
+		return "DNS: provided by the VPN (none configured)"
+	}
+	source := "in addition to the VPN's DNS"
+	if s.IgnoreAutoIPv4 {
+		source = "only, ignoring the VPN's DNS"
+	}
+	return fmt.Sprintf("DNS: %s (%s)", strings.Join(servers, ", "), source)
+}
+
+// setDNS pins the DNS servers of a connection and ignores the ones pushed by
+// the VPN server. An empty list goes back to the servers the VPN provides.
+func setDNS(vpnName string, servers []string) error {
+	var v4, v6 []string
+	for _, server := range servers {
+		ip := net.ParseIP(server)
+		if ip == nil {
+			return fmt.Errorf("%q is not an IP address", server)
+		}
+		if ip.To4() != nil {
+			v4 = append(v4, ip.String())
+		} else {
+			v6 = append(v6, ip.String())
+		}
+	}
+	
+	ignoreAuto := "yes"
+	if len(servers) == 0 {
+		ignoreAuto = "no"
+	}
+	args := []string{"connection", "modify", vpnName,
+		"ipv4.dns", strings.Join(v4, ","),
+		"ipv4.ignore-auto-dns", ignoreAuto,
+		"ipv6.dns", strings.Join(v6, ","),
+		"ipv6.ignore-auto-dns", ignoreAuto,
+	}
+	_, err := executeCommand("nmcli", args...)
+	return err
+}
+
+// vpnDNSServers returns the DNS servers an active connection ended up with,
+// whether pushed by the VPN or configured on the profile.
+func vpnDNSServers(vpnName string) []string {
+	output, err := executeCommand("nmcli", "-t", "-f", "IP4.DNS,IP6.DNS", "connection", "show", vpnName)
+	if err != nil {
+		return nil
+	}
+	var servers []string
+	for _, line := range outputLines(output) {
+		if _, value, ok := terseProperty(line); ok && value != "" {
+			servers = append(servers, value)
+		}
+	}
+	return servers
+}
+
+func resolvConfServers() ([]string, error) {
+	data, err := os.ReadFile("/etc/resolv.conf")
+	if err != nil {
+		return nil, err
+	}
+	var servers []string
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			servers = append(servers, fields[1])
+		}
+	}
+	return servers, nil
+}
+
+var resolvedLink = regexp.MustCompile(`-- link: (\S+)`)
+
+// resolvedServers asks systemd-resolved which link answered a lookup and
+// returns that link's DNS servers.
+func resolvedServers(ctx context.Context) ([]string, error) {
+	output, err := executeCommandContext(ctx, "", "resolvectl", "query", "--legend=no", dnsLeakHost)
+	if err != nil {
+		return nil, err
+	}
+	match := resolvedLink.FindStringSubmatch(output)
+	if match == nil {
+		return nil, fmt.Errorf("could not tell which link resolved %s", dnsLeakHost)
+	}
+	output, err = executeCommandContext(ctx, "", "resolvectl", "dns", match[1])
+	if err != nil {
+		return nil, err
+	}
+	_, list, _ := strings.Cut(output, ":")
+	return strings.Fields(list), nil
+}
+
+func isStubResolver(servers []string) bool {
+	for _, server := range servers {
+		if !strings.HasPrefix(server, "127.0.0.5") {
+			return false
+		}
+	}
+	return len(servers) > 0
+}
+
+const dnsLeakHost = "example.com"
+
+// checkDNSLeak reports whether lookups go to resolvers other than
+// expectedDNS, i.e. bypass the VPN's DNS.
+func checkDNSLeak(expectedDNS []string) (bool, error) {
+	if len(expectedDNS) == 0 {
+		return false, fmt.Errorf("the VPN does not provide DNS servers to compare against")
+	}
+	
+	servers, err := resolvConfServers()
+	if err != nil {
+		return false, err
+	}
+	if isStubResolver(servers) {
+		if _, err := exec.LookPath("resolvectl"); err != nil {
+			return false, fmt.Errorf("systemd-resolved is in use but resolvectl is not installed")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		if servers, err = resolvedServers(ctx); err != nil {
+			return false, err
+		}
+	}
+	if len(servers) == 0 {
+		return false, fmt.Errorf("no DNS servers found")
+	}
+	return !slices.Contains(expectedDNS, servers[0]), nil
+}
+
+func dnsLeakWarning(vpnName string) string {
+	expected := vpnDNSServers(vpnName)
+	leaking, err := checkDNSLeak(expected)
+	if err != nil {
+		return fmt.Sprintf("DNS leak check skipped: %s\n", err)
+	}
+	if leaking {
+		return warningStyle.Render(fmt.Sprintf("WARNING: DNS queries are not going to %s's DNS servers (%s)", vpnName, strings.Join(expected, ", "))) + "\n"
+	}
+	return "DNS: queries go through the VPN\n"
+}
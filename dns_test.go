@@ -0,0 +1,47 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSetDNS(t *testing.T) {
+	tests := []struct {
+		name    string
+		servers []string
+		want    []string
+	}{
+		{
+			name:    "IPv4 only",
+			servers: []string{"1.1.1.1", "9.9.9.9"},
+			want: []string{"nmcli", "connection", "modify", "Work",
+				"ipv4.dns", "1.1.1.1,9.9.9.9", "ipv4.ignore-auto-dns", "yes",
+				"ipv6.dns", "", "ipv6.ignore-auto-dns", "yes"},
+		},
+		{
+			name:    "both families",
+			servers: []string{"1.1.1.1", "2606:4700:4700::1111"},
+			want: []string{"nmcli", "connection", "modify", "Work",
+				"ipv4.dns", "1.1.1.1", "ipv4.ignore-auto-dns", "yes",
+				"ipv6.dns", "2606:4700:4700::1111", "ipv6.ignore-auto-dns", "yes"},
+		},
+		{
+			name:    "back to the VPN's servers",
+			servers: nil,
+			want: []string{"nmcli", "connection", "modify", "Work",
+				"ipv4.dns", "", "ipv4.ignore-auto-dns", "no",
+				"ipv6.dns", "", "ipv6.ignore-auto-dns", "no"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := useFakeRunner(t, nil)
+			if err := setDNS("Work", tt.servers); err != nil {
+				t.Fatal(err)
+			}
+			if len(fake.commands) != 1 || !reflect.DeepEqual(fake.commands[0].args, tt.want) {
+				t.Errorf("ran %+v, want %q", fake.commands, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	maxProfileSize  = 1 << 20
+	downloadTimeout = 30 * time.Second
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_.+=-]+`)
+
+// downloadClient refuses redirects away from HTTPS, so a profile that may
+// carry keys is never fetched in the clear.
+var downloadClient = &http.Client{
+	Timeout:       downloadTimeout,
+	CheckRedirect: httpsOnlyRedirect,
+}
+
+func httpsOnlyRedirect(req *http.Request, via []*http.Request) error {
+	if req.URL.Scheme != "https" {
+		return fmt.Errorf("refusing redirect to %s, only https:// URLs are supported", req.URL.Redacted())
+	}
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	return nil
+}
+
+// profileFileName picks the name of the downloaded profile. nmcli names the
+// imported connection after the file, so the URL's file name is kept when it
+// has a known extension.
+func profileFileName(u *url.URL, data []byte) string {
+	name := unsafeFileChars.ReplaceAllString(path.Base(u.Path), "-")
+	ext := strings.ToLower(filepath.Ext(name))
+	if ext == ".ovpn" || ext == ".conf" {
+		return name
+	}
+	if name == "" || name == "." || name == "-" {
+		name = u.Hostname()
+	}
+	if strings.Contains(string(data), "[Interface]") {
+		return name + ".conf"
+	}
+	return name + ".ovpn"
+}
+
+func checkProfileContentType(contentType string) error {
+	if contentType == "" {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("invalid content type %q", contentType)
+	}
+	if mediaType == "text/html" || strings.HasPrefix(mediaType, "image/") ||
+		strings.HasPrefix(mediaType, "audio/") || strings.HasPrefix(mediaType, "video/") {
+		return fmt.Errorf("server returned %s, not a VPN profile (is a login required?)", mediaType)
+	}
+	return nil
+}
+
+// downloadProfile fetches a profile over HTTPS into a temporary directory and
+// returns its path. The caller removes the directory once it is imported.
+func downloadProfile(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return "", errors.New("only https:// URLs are supported")
+	}
+	
+	ctx, cancel := context.WithTimeout(baseContext(), downloadTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := downloadClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download failed: %s", resp.Status)
+	}
+	if err := checkProfileContentType(resp.Header.Get("Content-Type")); err != nil {
+		return "", err
+	}
+	if resp.ContentLength > maxProfileSize {
+		return "", fmt.Errorf("profile is larger than %s", formatBytes(maxProfileSize))
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxProfileSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxProfileSize {
+		return "", fmt.Errorf("profile is larger than %s", formatBytes(maxProfileSize))
+	}
+	
+	dir, err := os.MkdirTemp("", "charmvpn-download-")
+	if err != nil {
+		return "", err
+	}
+	file := filepath.Join(dir, profileFileName(u, data))
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("writing downloaded profile: %w", err)
+	}
+	return file, nil
+}
+
+func importFromURL(rawURL string) (string, error) {
+	file, err := downloadProfile(rawURL)
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(filepath.Dir(file))
+	return addVPN(file)
+}
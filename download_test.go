@@ -0,0 +1,31 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestHTTPSOnlyRedirect(t *testing.T) {
+	tests := []struct {
+		target string
+		via    int
+		ok     bool
+	}{
+		{"https://example.com/work.ovpn", 1, true},
+		{"http://example.com/work.ovpn", 1, false},
+		{"ftp://example.com/work.ovpn", 1, false},
+		{"https://example.com/work.ovpn", 10, false},
+	}
+	for _, test := range tests {
+		u, err := url.Parse(test.target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		via := make([]*http.Request, test.via)
+		err = httpsOnlyRedirect(&http.Request{URL: u}, via)
+		if (err == nil) != test.ok {
+			t.Errorf("redirect to %s after %d: got %v, want ok=%v", test.target, test.via, err, test.ok)
+		}
+	}
+}
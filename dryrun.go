@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// dryRun is set by --dry-run and the "Toggle dry run" action.
+var dryRun bool
+
+// dryRunRunner prints commands that would change something instead of running
+// them and pretends they succeeded. Commands that only read state still run,
+// so menus and status keep working.
+type dryRunRunner struct {
+	next Runner
+}
+
+func (r dryRunRunner) Run(ctx context.Context, stdin string, name string, args ...string) (string, error) {
+	if isReadOnlyCommand(name, args) {
+		return r.next.Run(ctx, stdin, name, args...)
+	}
+	fmt.Fprintln(os.Stderr, dryRunStyle.Render("dry run: "+strings.Join(append([]string{name}, redactArgs(args)...), " ")))
+	return "", nil
+}
+
+func (r dryRunRunner) RunStdout(ctx context.Context, stdin string, name string, args ...string) (string, string, error) {
+	if next, ok := r.next.(StdoutRunner); ok && isReadOnlyCommand(name, args) {
+		return next.RunStdout(ctx, stdin, name, args...)
+	}
+	output, err := r.Run(ctx, stdin, name, args...)
+	return output, "", err
+}
+
+var dryRunStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+
+// dryRunSkips reports whether a dry run should leave what alone, a file or
+// keyring entry, printing that it would have been written.
+func dryRunSkips(what string) bool {
+	if !dryRun {
+		return false
+	}
+	fmt.Fprintln(os.Stderr, dryRunStyle.Render("dry run: would write "+what))
+	return true
+}
+
+// simulated labels output during a dry run, where results are only pretended.
+func simulated(output string) string {
+	if !dryRun || output == "" {
+		return output
+	}
+	return dryRunStyle.Render("dry run, simulated:") + "\n" + output
+}
+
+// setDryRun switches dry-run mode by wrapping or unwrapping the runner.
+func setDryRun(enabled bool) {
+	dryRun = enabled
+	if r, ok := runner.(dryRunRunner); ok {
+		runner = r.next
+	}
+	if enabled {
+		runner = dryRunRunner{next: runner}
+	}
+}
+
+// readOnlyCommands never change anything, whatever their arguments.
+var readOnlyCommands = []string{"ip", "ping", "resolvectl", "pass", "true", "cat", "ls"}
+
+// readOnlyVerbs are the nmcli, wg and nft subcommands that only read state.
+var readOnlyVerbs = []string{"show", "showconf", "status", "list", "export"}
+
+// isReadOnlyCommand tells commands that only look at the system from ones
+// that change it. Anything unknown counts as a change.
+func isReadOnlyCommand(name string, args []string) bool {
+	switch name {
+		case "sudo":
+			for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+				if args[0] == "-p" && len(args) > 1 {
+					args = args[1:]
+				}
+				args = args[1:]
+			}
+			if len(args) == 0 {
+				return false
+			}
+			return isReadOnlyCommand(args[0], args[1:])
+		case "nmcli":
+			return nmcliReadOnly(args)
+		case "wg", "nft":
+			return len(args) > 0 && slices.Contains(readOnlyVerbs, args[0])
+	}
+	return slices.Contains(readOnlyCommands, name)
+}
+
+// nmcliReadOnly looks past nmcli's options at the object and verb, as in
+// "nmcli -t -f NAME connection show". Without a verb nmcli shows the object.
+func nmcliReadOnly(args []string) bool {
+	var words []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+			case "-f", "-g", "--fields", "--get-values":
+				i++
+			default:
+				if !strings.HasPrefix(args[i], "-") {
+					words = append(words, args[i])
+				}
+		}
+	}
+	if len(words) < 2 {
+		return true
+	}
+	if words[1] == "wifi" {
+		return len(words) == 2 || words[2] == "list"
+	}
+	return slices.Contains(readOnlyVerbs, words[1])
+}
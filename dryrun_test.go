@@ -0,0 +1,28 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDryRunLeavesFilesAlone(t *testing.T) {
+	useFakeRunner(t, nil)
+	setDryRun(true)
+	defer setDryRun(false)
+	
+	recordConnected("Work")
+	rememberLastConnected("Work")
+	if err := saveConfig(Config{Favorites: []string{"Work"}}); err != nil {
+		t.Fatal(err)
+	}
+	if backup, err := backupVPN("Work"); err != nil || backup != "" {
+		t.Fatalf("backupVPN = %q, %v", backup, err)
+	}
+	
+	for _, path := range []func() (string, error){statePath, configPath, trashDir} {
+		p, _ := path()
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("dry run wrote %s", p)
+		}
+	}
+}
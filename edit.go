@@ -0,0 +1,225 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+)
+
+type VPNSettings struct {
+	ServiceType string
+	GatewayKey  string
+	Gateway     string
+	Port        string
+	Autoconnect bool
+	Priority    string
+	DNS         string
+	Data        map[string]string
+}
+
+func parseVPNData(value string) map[string]string {
+	data := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		data[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+	return data
+}
+
+func readVPNSettings(vpnName string) (VPNSettings, error) {
+	var settings VPNSettings
+	output, err := executeCommand("nmcli", "-t", "connection", "show", vpnName)
+	if err != nil {
+		return settings, err
+	}
+	
+	var vpnData map[string]string
+	for _, line := range outputLines(output) {
+		key, value, ok := terseProperty(line)
+		if !ok {
+			continue
+		}
+		switch key {
+			case "vpn.service-type":
+				settings.ServiceType = value
+			case "vpn.data":
+				vpnData = parseVPNData(value)
+			case "connection.autoconnect":
+				settings.Autoconnect = value == "yes"
+			case "connection.autoconnect-priority":
+				settings.Priority = value
+			case "ipv4.dns":
+				settings.DNS = value
+		}
+	}
+	
+	settings.Data = vpnData
+	if strings.HasSuffix(settings.ServiceType, "openvpn") {
+		settings.GatewayKey = "remote"
+		settings.Port = vpnData["port"]
+	} else if settings.ServiceType != "" {
+		settings.GatewayKey = "gateway"
+	}
+	if settings.GatewayKey != "" {
+		settings.Gateway = vpnData[settings.GatewayKey]
+	}
+	return settings, nil
+}
+
+func validatePort(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("port must be a number between 1 and 65535")
+	}
+	return nil
+}
+
+func validateDNSList(value string) error {
+	for _, server := range strings.Split(value, ",") {
+		server = strings.TrimSpace(server)
+		if server != "" && net.ParseIP(server) == nil {
+			return fmt.Errorf("%q is not an IP address", server)
+		}
+	}
+	return nil
+}
+
+func normalizeDNSList(value string) string {
+	var servers []string
+	for _, server := range strings.Split(value, ",") {
+		if server = strings.TrimSpace(server); server != "" {
+			servers = append(servers, server)
+		}
+	}
+	return strings.Join(servers, ",")
+}
+
+// modifyArgs returns the nmcli modify arguments for the fields that differ
+// between the current and the edited settings.
+func modifyArgs(current, edited VPNSettings) []string {
+	var args []string
+	if current.GatewayKey != "" && strings.TrimSpace(edited.Gateway) != current.Gateway {
+		args = append(args, "+vpn.data", current.GatewayKey+"="+strings.TrimSpace(edited.Gateway))
+	}
+	if strings.TrimSpace(edited.Port) != current.Port && strings.TrimSpace(edited.Port) != "" {
+		args = append(args, "+vpn.data", "port="+strings.TrimSpace(edited.Port))
+	}
+	if edited.Autoconnect != current.Autoconnect {
+		value := "no"
+		if edited.Autoconnect {
+			value = "yes"
+		}
+		args = append(args, "connection.autoconnect", value)
+	}
+	if prio := strings.TrimSpace(edited.Priority); prio != current.Priority && prio != "" {
+		args = append(args, "connection.autoconnect-priority", prio)
+	}
+	if dns := normalizeDNSList(edited.DNS); dns != normalizeDNSList(current.DNS) {
+		args = append(args, "ipv4.dns", dns)
+	}
+	return args
+}
+
+// settingKey matches nmcli property names like ipv4.dns-search, optionally
+// prefixed with + or - to add to or remove from a list.
+var settingKey = regexp.MustCompile(`^[+-]?[a-z0-9-]+\.[a-z0-9-]+$`)
+
+func validateSettingKey(key string) error {
+	key = strings.TrimSpace(key)
+	if key != "" && !settingKey.MatchString(key) {
+		return fmt.Errorf("enter a property like ipv4.dns-search or +vpn.data")
+	}
+	return nil
+}
+
+// modifyField sets a single connection property, for settings the edit form
+// doesn't cover.
+func modifyField(vpnName string, key string, value string) error {
+	key = strings.TrimSpace(key)
+	if err := validateSettingKey(key); err != nil {
+		return err
+	}
+	_, err := executeCommand("nmcli", "connection", "modify", vpnName, key, value)
+	return err
+}
+
+func editVPN(vpnName string) (string, error) {
+	current, err := readVPNSettings(vpnName)
+	if err != nil {
+		return "", err
+	}
+	edited := current
+	
+	var fields []huh.Field
+	if current.GatewayKey != "" {
+		fields = append(fields, huh.NewInput().
+			Title("Server ("+current.GatewayKey+")").
+			Value(&edited.Gateway))
+	}
+	if strings.HasSuffix(current.ServiceType, "openvpn") {
+		fields = append(fields, huh.NewInput().
+			Title("Port").
+			Placeholder("1194").
+			Value(&edited.Port).
+			Validate(validatePort))
+	}
+	fields = append(fields,
+		huh.NewConfirm().
+			Title("Connect automatically?").
+			Value(&edited.Autoconnect),
+		huh.NewInput().
+			Title("Autoconnect priority").
+			Description("-999 to 999; the highest wins when several VPNs connect automatically").
+			Value(&edited.Priority).
+			Validate(validatePriority),
+		huh.NewInput().
+			Title("DNS servers (comma separated)").
+			Value(&edited.DNS).
+			Validate(validateDNSList),
+	)
+	
+	var customKey, customValue string
+	form := newForm(
+		newGroup(fields...),
+		newGroup(
+			huh.NewInput().
+				Title("Custom property (optional)").
+				Description("Any nmcli connection property, e.g. ipv4.dns-search").
+				Value(&customKey).
+				Validate(validateSettingKey),
+			huh.NewInput().
+				Title("Value").
+				Value(&customValue),
+		),
+	)
+	if err := form.Run(); err != nil {
+		return "", nil
+	}
+	
+	args := modifyArgs(current, edited)
+	if len(args) == 0 && strings.TrimSpace(customKey) == "" {
+		return "No changes", nil
+	}
+	if len(args) > 0 {
+		if _, err := executeCommand("nmcli", append([]string{"connection", "modify", vpnName}, args...)...); err != nil {
+			return "", err
+		}
+	}
+	if strings.TrimSpace(customKey) != "" {
+		if err := modifyField(vpnName, customKey, customValue); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("Updated %s", vpnName), nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+type ExportResult struct {
+	Name string
+	File string
+	Err  error
+}
+
+// exportFileName is the file vpn is exported to by exportAll. Names that
+// would collide, such as duplicate connection names, get the UUID appended.
+func exportFileName(vpn VPN, vpnType string, used map[string]bool) string {
+	base := strings.NewReplacer("/", "_", "\x00", "").Replace(vpn.Name)
+	name := base + exportExtension(vpnType)
+	if used[strings.ToLower(name)] && vpn.UUID != "" {
+		name = fmt.Sprintf("%s-%.8s%s", base, vpn.UUID, exportExtension(vpnType))
+	}
+	used[strings.ToLower(name)] = true
+	return name
+}
+
+// exportAll exports every connection into dir. The caller is expected to
+// have made sure dir exists and warned that configs may contain secrets.
+func exportAll(dir string) []ExportResult {
+	vpns := getVPNs()
+	ids := vpnIDs(vpns)
+	allow := func(string, string) bool { return true }
+	
+	used := map[string]bool{}
+	results := make([]ExportResult, 0, len(vpns))
+	for i, vpn := range vpns {
+		result := ExportResult{Name: vpnDisplayName(ids[i])}
+		vpnType, err := connectionVPNType(ids[i])
+		if err != nil {
+			result.Err = err
+			results = append(results, result)
+			continue
+		}
+		result.File = exportFileName(vpn, vpnType, used)
+		_, result.Err = exportVPN(ids[i], filepath.Join(dir, result.File), allow)
+		logVerbose("export %s: %v", result.Name, result.Err)
+		results = append(results, result)
+	}
+	return results
+}
+
+func formatExportResults(dir string, results []ExportResult) string {
+	var exported, failed strings.Builder
+	var failures int
+	for _, result := range results {
+		if result.Err != nil {
+			failures++
+			failed.WriteString(fmt.Sprintf("  %s: %v\n", result.Name, result.Err))
+			continue
+		}
+		exported.WriteString(fmt.Sprintf("  %s -> %s\n", result.Name, result.File))
+	}
+	
+	var summary strings.Builder
+	summary.WriteString(fmt.Sprintf("Exported %d of %d connections to %s\n", len(results)-failures, len(results), dir))
+	summary.WriteString(exported.String())
+	if failures > 0 {
+		summary.WriteString(fmt.Sprintf("Failed (%d):\n", failures))
+		summary.WriteString(failed.String())
+	}
+	return summary.String()
+}
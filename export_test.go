@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSafeExportPath(t *testing.T) {
+	root := t.TempDir()
+	base := filepath.Join(root, "exports")
+	outside := filepath.Join(root, "outside")
+	for _, dir := range []string{base, outside} {
+		if err := os.Mkdir(dir, 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(outside, filepath.Join(base, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	linkedBase := filepath.Join(root, "linked")
+	if err := os.Symlink(base, linkedBase); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", base)
+	
+	tests := []struct {
+		name  string
+		input string
+		base  string
+		want  string
+		ok    bool
+	}{
+		{"inside", filepath.Join(base, "work.ovpn"), base, filepath.Join(base, "work.ovpn"), true},
+		{"subdirectory", filepath.Join(base, "new", "work.ovpn"), base, filepath.Join(base, "new", "work.ovpn"), true},
+		{"parent", filepath.Join(base, "..", "work.ovpn"), base, "", false},
+		{"parent in a name with slashes", filepath.Join(base, "Work/../../work.ovpn"), base, "", false},
+		{"slashes inside", filepath.Join(base, "Work/Home/work.ovpn"), base, filepath.Join(base, "Work", "Home", "work.ovpn"), true},
+		{"absolute outside", "/etc/work.ovpn", base, "", false},
+		{"sibling with the same prefix", base + "-old/work.ovpn", base, "", false},
+		{"through a symlink out", filepath.Join(base, "escape", "work.ovpn"), base, "", false},
+		{"symlinked export_dir", filepath.Join(linkedBase, "work.ovpn"), linkedBase, filepath.Join(linkedBase, "work.ovpn"), true},
+		{"real path of a symlinked export_dir", filepath.Join(base, "work.ovpn"), linkedBase, filepath.Join(base, "work.ovpn"), true},
+		{"unrestricted", "/etc/work.ovpn", "", "/etc/work.ovpn", true},
+		{"home", "~", base, base, true},
+		{"under home", "~/sub", base, filepath.Join(base, "sub"), true},
+		{"home outside export_dir", "~", outside, "", false},
+		{"under home outside export_dir", "~/sub", outside, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := safeExportPath(tt.input, tt.base)
+			if (err == nil) != tt.ok || got != tt.want {
+				t.Errorf("safeExportPath(%q, %q) = %q, %v, want %q, ok=%v", tt.input, tt.base, got, err, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestDefaultExportNameStaysInDirectory(t *testing.T) {
+	if got := defaultExportName("../../etc/Work", "openvpn"); got != "..-..-etc-Work.ovpn" {
+		t.Errorf("defaultExportName() = %q", got)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+func isFavorite(vpnName string) bool {
+	for _, favorite := range config.Favorites {
+		if favorite == configName(vpnName) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortFavoritesFirst(vpns []string) {
+	sort.SliceStable(vpns, func(i, j int) bool {
+		return isFavorite(vpns[i]) && !isFavorite(vpns[j])
+	})
+}
+
+func addFavorite(vpnName string) error {
+	if isFavorite(vpnName) {
+		return nil
+	}
+	cfg := config
+	cfg.Favorites = append(append([]string(nil), config.Favorites...), configName(vpnName))
+	if err := saveConfig(cfg); err != nil {
+		return fmt.Errorf("saving favorites: %w", err)
+	}
+	config = cfg
+	return nil
+}
+
+func removeFavorite(vpnName string) error {
+	var favorites []string
+	for _, favorite := range config.Favorites {
+		if favorite != configName(vpnName) {
+			favorites = append(favorites, favorite)
+		}
+	}
+	cfg := config
+	cfg.Favorites = favorites
+	if err := saveConfig(cfg); err != nil {
+		return fmt.Errorf("saving favorites: %w", err)
+	}
+	config = cfg
+	return nil
+}
+
+// favoriteVPNs returns the favorites that still exist.
+func favoriteVPNs() []string {
+	var favorites []string
+	for _, vpn := range getVPNList() {
+		if isFavorite(vpn) {
+			favorites = append(favorites, vpn)
+		}
+	}
+	return favorites
+}
+
+// connectFavorite connects a favorite VPN without the main menu: the only
+// one right away, otherwise the one picked from the favorites. ok is false
+// when there are no favorites, so the caller can show the menu instead.
+func connectFavorite() (ok bool, err error) {
+	favorites := favoriteVPNs()
+	if len(favorites) == 0 {
+		return false, nil
+	}
+	
+	selected := favorites[0]
+	if len(favorites) > 1 {
+		selected = lastConnectedVPN(favorites)
+		form := newForm(
+			newGroup(
+				vpnSelect("Connect to favorite", &selected, favorites),
+			),
+		)
+		if err := form.Run(); err != nil || selected == "" {
+			return true, nil
+		}
+	}
+	if !confirmCaptivePortal() {
+		return true, nil
+	}
+	
+	var result ConnectResult
+	var output string
+	if interruptible(func() {
+		result, output = timedConnect(selected, func(vpnName string) (string, error) {
+			return connectInteractiveWith(vpnName, connectOptions{})
+		})
+	}) {
+		abortConnect(selected)
+	}
+	printResult(strings.TrimSuffix(output+result.Summary(), "\n"), result.Err)
+	return true, result.Err
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+const filterThreshold = 10
+
+// noColor is set when the NO_COLOR environment variable is present.
+var noColor = os.Getenv("NO_COLOR") != ""
+
+// setupOutput drops colors for NO_COLOR and --plain.
+func setupOutput() {
+	if noColor || plain {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
+
+// formTheme is the huh theme of every form, picked with the theme config key.
+var formTheme = huh.ThemeCharm()
+
+func selectTheme(name string) (*huh.Theme, error) {
+	switch name {
+		case "charm", "":
+			return huh.ThemeCharm(), nil
+		case "dracula":
+			return huh.ThemeDracula(), nil
+		case "catppuccin":
+			return huh.ThemeCatppuccin(), nil
+		case "base16":
+			return huh.ThemeBase16(), nil
+		case "base":
+			return huh.ThemeBase(), nil
+	}
+	return nil, fmt.Errorf("unknown theme %q (expected charm, dracula, catppuccin, base16 or base)", name)
+}
+
+// escCancels reports whether ESC should back out of form, like Ctrl-C. It
+// shouldn't while the focused field uses ESC itself, as selects do to leave
+// or clear a filter and the file picker does to stop browsing.
+func escCancels(form *huh.Form) bool {
+	for _, binding := range form.KeyBinds() {
+		if binding.Enabled() && slices.Contains(binding.Keys(), "esc") {
+			return false
+		}
+	}
+	return true
+}
+
+// filtering reports whether the focused select of form is taking a filter,
+// which huh signals by enabling the binding that sets it.
+func filtering(form *huh.Form) bool {
+	setFilter := huh.NewDefaultKeyMap().Select.SetFilter.Help()
+	for _, binding := range form.KeyBinds() {
+		if binding.Enabled() && binding.Help() == setFilter {
+			return true
+		}
+	}
+	return false
+}
+
+// escFilter turns ESC into Ctrl-C when escCancels, so forms return
+// huh.ErrUserAborted, which callers treat as going back to the menu. q isn't
+// bound since pickers filter and inputs take text as it is typed.
+func escFilter(model tea.Model, msg tea.Msg) tea.Msg {
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.Type == tea.KeyEsc {
+		if form, ok := model.(*huh.Form); ok && escCancels(form) {
+			return tea.KeyMsg{Type: tea.KeyCtrlC}
+		}
+	}
+	return msg
+}
+
+// newForm builds a form in the app's theme from groups made by newGroup. With
+// --plain its Run prints numbered choices and reads answers line by line
+// instead of drawing a full-screen UI.
+func newForm(groups ...*huh.Group) *Form {
+	theme := formTheme
+	if noColor || plain {
+		theme = huh.ThemeBase()
+	}
+	form := &Form{Form: huh.NewForm(groups...).
+		WithTheme(theme).
+		WithProgramOptions(tea.WithOutput(os.Stderr), tea.WithFilter(escFilter))}
+	groupFields.Lock()
+	for _, group := range groups {
+		form.fields = append(form.fields, groupFields.m[group]...)
+		delete(groupFields.m, group)
+	}
+	groupFields.Unlock()
+	return form
+}
+
+// confirm asks a yes/no question, defaulting to no.
+func confirm(title string, description string) bool {
+	var confirmed bool
+	form := newForm(
+		newGroup(
+			huh.NewConfirm().
+				Title(title).
+				Description(description).
+				Value(&confirmed),
+		),
+	)
+	return form.Run() == nil && confirmed
+}
+
+// vpnSelect builds a selector over vpns, starting in filter mode for long
+// lists so users can type to narrow them down.
+func vpnSelect(title string, value *string, vpns []string) *huh.Select[string] {
+	autoconnect := autoconnectEnabled()
+	options := make([]huh.Option[string], len(vpns))
+	for i, vpn := range vpns {
+		options[i] = huh.NewOption(vpnLabel(vpn)+autoconnectMarker(autoconnect[vpn]), vpn)
+	}
+	return huh.NewSelect[string]().
+		Title(title).
+		Value(value).
+		Options(options...).
+		Filtering(len(vpns) > filterThreshold)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+)
+
+func sendKey(form *huh.Form, msg tea.KeyMsg) {
+	form.Update(escFilter(form, msg))
+}
+
+func TestEscLeavesFilterBeforeCancelling(t *testing.T) {
+	var value string
+	form := newForm(newGroup(huh.NewSelect[string]().
+		Options(huh.NewOptions("Work", "Tokyo", "Home")...).
+		Value(&value))).Form
+	form.Init()
+	
+	if !escCancels(form) {
+		t.Fatal("ESC should cancel a select that isn't filtering")
+	}
+	sendKey(form, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	if escCancels(form) {
+		t.Fatal("ESC should leave the filter, not cancel the form")
+	}
+	sendKey(form, tea.KeyMsg{Type: tea.KeyEsc})
+	if form.State != huh.StateNormal {
+		t.Fatalf("form state after leaving the filter = %v, want normal", form.State)
+	}
+	sendKey(form, tea.KeyMsg{Type: tea.KeyEsc})
+	if form.State != huh.StateAborted {
+		t.Fatalf("form state after a second ESC = %v, want aborted", form.State)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+type VPNGroup struct {
+	Name string
+	VPNs []string
+}
+
+var vpnTypeNames = map[string]string{
+	"openvpn":     "OpenVPN",
+	"wireguard":   "WireGuard",
+	"vpnc":        "Cisco (vpnc)",
+	"openconnect": "OpenConnect",
+	"l2tp":        "L2TP",
+	"pptp":        "PPTP",
+	"strongswan":  "IPsec/IKEv2",
+}
+
+func vpnTypeName(vpnType string) string {
+	if name, ok := vpnTypeNames[vpnType]; ok {
+		return name
+	}
+	if vpnType == "" || vpnType == "vpn" {
+		return "Other"
+	}
+	return vpnType
+}
+
+// vpnServiceTypes resolves the plugin (openvpn, vpnc, ...) behind nmcli's
+// generic "vpn" connection type with a single nmcli call.
+func vpnServiceTypes(names []string) map[string]string {
+	types := map[string]string{}
+	if len(names) == 0 {
+		return types
+	}
+	args := append([]string{"-t", "-f", "connection.id,vpn.service-type", "connection", "show"}, names...)
+	output, err := executeCommand("nmcli", args...)
+	if err != nil {
+		return types
+	}
+	
+	var current string
+	for _, line := range outputLines(output) {
+		key, value, ok := terseProperty(line)
+		if !ok {
+			continue
+		}
+		switch key {
+			case "connection.id":
+				current = value
+			case "vpn.service-type":
+				types[current] = value[strings.LastIndex(value, ".")+1:]
+		}
+	}
+	return types
+}
+
+// vpnTypes returns the type of each VPN by identifier, with nmcli's generic
+// "vpn" type resolved to the plugin behind it.
+func vpnTypes() map[string]string {
+	types := map[string]string{}
+	var generic []string
+	if list, err := cachedVPNList(); err == nil {
+		for i, id := range vpnIDs(list) {
+			types[id] = list[i].Type
+			if list[i].Type == "vpn" {
+				generic = append(generic, id)
+			}
+		}
+	}
+	if usingNmcli() {
+		for name, vpnType := range vpnServiceTypes(generic) {
+			types[name] = vpnType
+		}
+	}
+	return types
+}
+
+// groupVPNs groups vpns by the provider set for them in the config, falling
+// back to their type from vpnTypes. Groups are sorted by name and keep the
+// order of vpns.
+func groupVPNs(vpns []string, types map[string]string) []VPNGroup {
+	index := map[string]int{}
+	var groups []VPNGroup
+	for _, vpn := range vpns {
+		name := strings.TrimSpace(config.Providers[configName(vpn)])
+		if name == "" {
+			name = vpnTypeName(types[vpn])
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, VPNGroup{Name: name})
+		}
+		groups[i].VPNs = append(groups[i].VPNs, vpn)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return strings.ToLower(groups[i].Name) < strings.ToLower(groups[j].Name)
+	})
+	return groups
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	EventPreConnect  = "pre_connect"
+	EventConnect     = "on_connect"
+	EventDisconnect  = "on_disconnect"
+	EventConnectFail = "on_connect_fail"
+	EventReconnect   = "on_reconnect"
+)
+
+type HooksConfig struct {
+	PreConnect  string `toml:"pre_connect"`
+	Connect     string `toml:"on_connect"`
+	Disconnect  string `toml:"on_disconnect"`
+	ConnectFail string `toml:"on_connect_fail"`
+	Reconnect   string `toml:"on_reconnect"`
+}
+
+// VPNHooks are commands run after one particular VPN comes up or goes down.
+type VPNHooks struct {
+	Up   string `toml:"up"`
+	Down string `toml:"down"`
+}
+
+const (
+	PhaseUp   = "up"
+	PhaseDown = "down"
+)
+
+const hookTimeout = 30 * time.Second
+
+func (h HooksConfig) command(event string) string {
+	switch event {
+		case EventPreConnect:
+			return h.PreConnect
+		case EventConnect:
+			return h.Connect
+		case EventDisconnect:
+			return h.Disconnect
+		case EventConnectFail:
+			return h.ConnectFail
+		case EventReconnect:
+			return h.Reconnect
+	}
+	return ""
+}
+
+// runEventHook runs the configured hook for event. Events without a configured
+// hook succeed trivially.
+func runEventHook(event string, vpnName string) (string, error) {
+	return runHookCommand(config.Hooks.command(event), vpnName, event)
+}
+
+// runHookCommand runs hook through sh, bounded by hookTimeout, passing the VPN
+// name and event both as positional arguments ($1, $2) and as environment
+// variables. An empty hook succeeds trivially.
+func runHookCommand(hook string, vpnName string, event string) (string, error) {
+	if strings.TrimSpace(hook) == "" {
+		return "", nil
+	}
+	
+	output, err := executeCommandTimeout(hookTimeout, "env",
+		"CHARMVPN_EVENT="+event,
+		"CHARMVPN_VPN="+vpnName,
+		"sh", "-c", hook, "charmvpn-hook", vpnName, event)
+	return output, timeoutError(err, event+" hook", hookTimeout)
+}
+
+func withEventHookWarning(err error, event string, vpnName string) error {
+	if warning := eventHookWarning(event, vpnName); warning != "" {
+		return fmt.Errorf("%w\n%s", err, strings.TrimSpace(warning))
+	}
+	return err
+}
+
+func eventHookWarning(event string, vpnName string) string {
+	if _, err := runEventHook(event, vpnName); err != nil {
+		return fmt.Sprintf("Warning: %s hook failed: %s\n", event, err)
+	}
+	return ""
+}
+
+// runHook runs the up or down hook configured for vpnName. VPNs without such a
+// hook succeed trivially.
+func runHook(vpnName string, phase string) (string, error) {
+	hooks := config.VPNHooks[configName(vpnName)]
+	hook := hooks.Up
+	if phase == PhaseDown {
+		hook = hooks.Down
+	}
+	return runHookCommand(hook, vpnName, phase)
+}
+
+// vpnHookOutput runs the up or down hook of vpnName and returns its output, or
+// a warning when it failed. A failing hook never undoes the connection change.
+func vpnHookOutput(vpnName string, phase string) string {
+	output, err := runHook(vpnName, phase)
+	if err != nil {
+		return fmt.Sprintf("Warning: %s hook of %s failed: %s\n", phase, vpnName, err)
+	}
+	if output = strings.TrimSpace(output); output != "" {
+		return output + "\n"
+	}
+	return ""
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type deadlineRunner struct {
+	deadline time.Time
+	ok       bool
+}
+
+func (r *deadlineRunner) Run(ctx context.Context, stdin string, name string, args ...string) (string, error) {
+	r.deadline, r.ok = ctx.Deadline()
+	return "", nil
+}
+
+func TestEventHooksAreBounded(t *testing.T) {
+	useFakeRunner(t, nil)
+	fake := &deadlineRunner{}
+	runner = fake
+	config.Hooks.Reconnect = "sleep 60"
+	
+	if _, err := runEventHook(EventReconnect, "Work"); err != nil {
+		t.Fatal(err)
+	}
+	if !fake.ok {
+		t.Fatal("on_reconnect hook ran without a deadline")
+	}
+	if left := time.Until(fake.deadline); left > hookTimeout {
+		t.Errorf("on_reconnect hook deadline %s away, want at most %s", left, hookTimeout)
+	}
+}
+
+func TestUnconfiguredEventHookRunsNothing(t *testing.T) {
+	fake := useFakeRunner(t, nil)
+	if _, err := runEventHook(EventReconnect, "Work"); err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.commands) != 0 {
+		t.Errorf("ran %+v without a configured hook", fake.commands)
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+)
+
+type ImportResult struct {
+	File string
+	Name string
+	Err  error
+}
+
+var importedNamePattern = regexp.MustCompile(`Connection '(.+)' \(`)
+
+// parseImportedName extracts the connection name from nmcli's
+// "Connection 'client' (<uuid>) successfully added." import output.
+func parseImportedName(output string) (string, bool) {
+	if match := importedNamePattern.FindStringSubmatch(output); match != nil {
+		return match[1], true
+	}
+	return "", false
+}
+
+// reportImport shows the result of importing a single profile and offers to
+// connect to the new connection right away.
+func reportImport(output string, err error) {
+	name, ok := parseImportedName(output)
+	if err != nil || !ok {
+		printResult(output, err)
+		return
+	}
+	fmt.Println("Imported as:", name)
+	
+	var connect bool
+	connectForm := newForm(
+		newGroup(
+			huh.NewConfirm().
+				Title(fmt.Sprintf("Connect to %s now?", name)).
+				Value(&connect),
+		),
+	)
+	if err := connectForm.Run(); err == nil && connect {
+		printResult(connectInteractive(name))
+	}
+}
+
+// importStartDir is where the file picker opens: ~/Downloads when it exists,
+// since that's where provider profiles usually land.
+func importStartDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "."
+	}
+	if info, err := os.Stat(filepath.Join(home, "Downloads")); err == nil && info.IsDir() {
+		return filepath.Join(home, "Downloads")
+	}
+	return home
+}
+
+// scanAvailableConfigs lists the .ovpn and .conf files in dir, sorted.
+func scanAvailableConfigs(dir string) ([]string, error) {
+	dir, err := expandHome(dir)
+	if err != nil {
+		return nil, err
+	}
+	
+	var files []string
+	for _, pattern := range []string{"*.ovpn", "*.conf"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// configImported reports whether the profile in file already has a
+// connection. nmcli names imported connections after the file.
+func configImported(file string, vpns []string) bool {
+	name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	for _, vpn := range vpns {
+		if vpn == name {
+			return true
+		}
+	}
+	return false
+}
+
+func importDirectory(dir string) ([]ImportResult, error) {
+	files, err := scanAvailableConfigs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no .ovpn or .conf files found in %s", dir)
+	}
+	return importFiles(files), nil
+}
+
+func importFiles(files []string) []ImportResult {
+	results := make([]ImportResult, 0, len(files))
+	for _, file := range files {
+		output, err := addVPN(file)
+		logVerbose("import %s: %v", file, err)
+		name, _ := parseImportedName(output)
+		results = append(results, ImportResult{
+			File: filepath.Base(file),
+			Name: name,
+			Err:  err,
+		})
+	}
+	return results
+}
+
+func formatImportResults(results []ImportResult) string {
+	var imported, failed strings.Builder
+	var failures int
+	for _, result := range results {
+		if result.Err != nil {
+			failures++
+			failed.WriteString(fmt.Sprintf("  %s: %v\n", result.File, result.Err))
+			continue
+		}
+		name := result.Name
+		if name == "" {
+			name = strings.TrimSuffix(result.File, filepath.Ext(result.File))
+		}
+		imported.WriteString(fmt.Sprintf("  %s -> %s\n", result.File, name))
+	}
+	
+	var summary strings.Builder
+	summary.WriteString(fmt.Sprintf("Imported %d of %d profiles\n", len(results)-failures, len(results)))
+	summary.WriteString(imported.String())
+	if failures > 0 {
+		summary.WriteString(fmt.Sprintf("Failed (%d):\n", failures))
+		summary.WriteString(failed.String())
+	}
+	return summary.String()
+}
@@ -0,0 +1,57 @@
+package main
+
+import "testing"
+
+func TestParseImportedName(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+		ok     bool
+	}{
+		{
+			name:   "openvpn profile",
+			output: "Connection 'client' (2f5a3b1c-7d6e-4f8a-9b0c-1d2e3f4a5b6c) successfully added.\n",
+			want:   "client",
+			ok:     true,
+		},
+		{
+			name:   "name with spaces and quotes",
+			output: "Connection 'Bob's home VPN' (2f5a3b1c-7d6e-4f8a-9b0c-1d2e3f4a5b6c) successfully added.\n",
+			want:   "Bob's home VPN",
+			ok:     true,
+		},
+		{
+			name:   "name with parentheses",
+			output: "Connection 'Work (backup)' (2f5a3b1c-7d6e-4f8a-9b0c-1d2e3f4a5b6c) successfully added.\n",
+			want:   "Work (backup)",
+			ok:     true,
+		},
+		{
+			name:   "after plugin warnings",
+			output: "Warning: option 'block-outside-dns' is not supported\nConnection 'wg0' (2f5a3b1c-7d6e-4f8a-9b0c-1d2e3f4a5b6c) successfully added.\n",
+			want:   "wg0",
+			ok:     true,
+		},
+		{
+			name:   "failed import",
+			output: "Error: failed to import 'client.ovpn': the file to import wasn't a valid VPN configuration.\n",
+		},
+		{
+			name:   "missing plugin",
+			output: "Error: failed to find VPN plugin for openconnect.\n",
+		},
+		{
+			name:   "no output",
+			output: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseImportedName(tt.output)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("parseImportedName(%q) = %q, %v, want %q, %v", tt.output, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
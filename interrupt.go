@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+var (
+	commandCtxMu sync.Mutex
+	commandCtx   = context.Background()
+
+	// interruptOwners counts the notifyInterrupt contexts in use.
+	interruptOwners atomic.Int32
+)
+
+// notifyInterrupt is signal.NotifyContext for code that handles Ctrl-C itself,
+// such as loops that run until interrupted and then return to the menu.
+func notifyInterrupt(signals ...os.Signal) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), signals...)
+	interruptOwners.Add(1)
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() { interruptOwners.Add(-1) })
+		stop()
+	}
+}
+
+// interruptHandled reports whether a notifyInterrupt context is handling
+// Ctrl-C right now.
+func interruptHandled() bool {
+	return interruptOwners.Load() > 0
+}
+
+// baseContext is the context commands are started under. Inside interruptible
+// it is cancelled by Ctrl-C, which kills the running command.
+func baseContext() context.Context {
+	commandCtxMu.Lock()
+	defer commandCtxMu.Unlock()
+	return commandCtx
+}
+
+// interruptible runs fn so that SIGINT or SIGTERM cancels the commands it
+// starts instead of killing charmvpn halfway through. It reports whether fn
+// was interrupted.
+func interruptible(fn func()) bool {
+	ctx, stop := notifyInterrupt(os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	
+	commandCtxMu.Lock()
+	commandCtx = ctx
+	commandCtxMu.Unlock()
+	defer func() {
+		commandCtxMu.Lock()
+		commandCtx = context.Background()
+		commandCtxMu.Unlock()
+	}()
+	
+	fn()
+	return ctx.Err() != nil
+}
+
+// abortConnect cleans up after a connection attempt was interrupted: the
+// half-established connection is taken down, kill switch rules are removed,
+// and charmvpn exits.
+func abortConnect(vpnName string) {
+	fmt.Fprintln(os.Stderr, "Interrupted, cleaning up...")
+	if _, err := backend.Disconnect(vpnName); err == nil {
+		forgetConnected(vpnName)
+	}
+	if err := disableKillSwitch(false); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to remove kill switch rules:", err)
+	}
+	os.Exit(130)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ipv6ProbeAddress is a public IPv6 address whose route shows which device
+// IPv6 traffic leaves through.
+const ipv6ProbeAddress = "2001:4860:4860::8888"
+
+var routeDevice = regexp.MustCompile(`\bdev (\S+)`)
+
+// checkIPv6Leak reports whether IPv6 traffic is routed outside the interfaces
+// of the active VPNs, i.e. still over the ISP. Without an IPv6 route there is
+// nothing to leak.
+func checkIPv6Leak() (bool, error) {
+	ctx, cancel := context.WithTimeout(baseContext(), 3*time.Second)
+	defer cancel()
+	output, err := executeCommandContext(ctx, "", "ip", "-6", "route", "get", ipv6ProbeAddress)
+	if err != nil {
+		if strings.Contains(strings.ToLower(output), "unreachable") {
+			return false, nil
+		}
+		return false, err
+	}
+	match := routeDevice.FindStringSubmatch(output)
+	if match == nil {
+		return false, fmt.Errorf("could not tell which device IPv6 traffic uses")
+	}
+	for _, vpn := range getActiveVPNs() {
+		if vpnInterface(vpn) == match[1] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ipv6LeakWarning checks for an IPv6 leak after vpnName connected and reports
+// whether one was found.
+func ipv6LeakWarning(vpnName string) (string, bool) {
+	leaking, err := checkIPv6Leak()
+	if err != nil {
+		return fmt.Sprintf("IPv6 leak check skipped: %s\n", err), false
+	}
+	if leaking {
+		return warningStyle.Render(fmt.Sprintf("WARNING: IPv6 traffic bypasses %s and goes out over your regular connection", vpnName)) + "\n", true
+	}
+	return "", false
+}
+
+// disableIPv6 stops the connection from configuring IPv6, from its next
+// activation on.
+func disableIPv6(vpnName string) error {
+	_, err := executeCommand("nmcli", "connection", "modify", vpnName, "ipv6.method", "ignore")
+	return err
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zalando/go-keyring"
+)
+
+const keyringService = "charmvpn"
+
+func saveSecret(vpnName string, secret string) error {
+	if dryRunSkips("the keyring entry for " + vpnName) {
+		return nil
+	}
+	if err := keyring.Set(keyringService, vpnName, secret); err != nil {
+		return fmt.Errorf("could not save to the system keyring: %w", err)
+	}
+	return nil
+}
+
+// loadSecret returns the password stored for vpnName, or keyring.ErrNotFound.
+func loadSecret(vpnName string) (string, error) {
+	return keyring.Get(keyringService, vpnName)
+}
+
+// clearSecret removes the stored password for vpnName. Having none stored is
+// not an error.
+func clearSecret(vpnName string) error {
+	if dryRunSkips("the keyring entry for " + vpnName) {
+		return nil
+	}
+	if err := keyring.Delete(keyringService, vpnName); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("could not clear the system keyring entry: %w", err)
+	}
+	return nil
+}
+
+func keyringSecret(vpnName string) string {
+	secret, err := loadSecret(vpnName)
+	if err != nil {
+		if !errors.Is(err, keyring.ErrNotFound) {
+			logVerbose("keyring lookup for %s failed: %v", vpnName, err)
+		}
+		return ""
+	}
+	return secret
+}
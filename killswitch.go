@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+const killSwitchTable = "charmvpn_killswitch"
+
+var (
+	killSwitchMu     sync.Mutex
+	killSwitchActive bool
+	killSwitchVPN    string
+	killSwitchStop   chan struct{}
+)
+
+func killSwitchAvailable() bool {
+	_, err := exec.LookPath("nft")
+	return err == nil
+}
+
+// vpnServerAddresses resolves the remote endpoint of a connection so the
+// tunnel itself can still reach it while everything else is blocked.
+func vpnServerAddresses(vpnName string) []string {
+	var hosts []string
+	if settings, err := readVPNSettings(vpnName); err == nil && settings.Gateway != "" {
+		for _, remote := range strings.Split(settings.Gateway, ",") {
+			host := strings.Fields(strings.TrimSpace(remote))
+			if len(host) == 0 {
+				continue
+			}
+			if h, _, err := net.SplitHostPort(host[0]); err == nil {
+				hosts = append(hosts, h)
+			} else {
+				hosts = append(hosts, strings.Split(host[0], ":")[0])
+			}
+		}
+	}
+	if iface := vpnInterface(vpnName); iface != "" {
+		if output, err := executeCommand("sudo", "wg", "show", iface, "endpoints"); err == nil {
+			for _, line := range strings.Split(output, "\n") {
+				fields := strings.Fields(line)
+				if len(fields) < 2 {
+					continue
+				}
+				if h, _, err := net.SplitHostPort(fields[1]); err == nil {
+					hosts = append(hosts, h)
+				}
+			}
+		}
+	}
+	
+	var addrs []string
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			addrs = append(addrs, ip.String())
+			continue
+		}
+		resolved, err := net.LookupHost(host)
+		if err != nil {
+			logVerbose("kill switch: could not resolve %s: %v", host, err)
+			continue
+		}
+		addrs = append(addrs, resolved...)
+	}
+	return addrs
+}
+
+func killSwitchRules(iface string, servers []string) string {
+	var rules strings.Builder
+	rules.WriteString(fmt.Sprintf("table inet %s {\n", killSwitchTable))
+	rules.WriteString("\tchain output {\n")
+	rules.WriteString("\t\ttype filter hook output priority 0; policy drop;\n")
+	rules.WriteString("\t\toifname \"lo\" accept\n")
+	rules.WriteString(fmt.Sprintf("\t\toifname %q accept\n", iface))
+	for _, server := range servers {
+		family := "ip"
+		if strings.Contains(server, ":") {
+			family = "ip6"
+		}
+		rules.WriteString(fmt.Sprintf("\t\t%s daddr %s accept\n", family, server))
+	}
+	rules.WriteString("\t}\n")
+	rules.WriteString("}\n")
+	return rules.String()
+}
+
+// enableKillSwitch drops all outbound traffic that does not leave through
+// iface, the interface of vpnName. The rules stay in place if the VPN drops,
+// until disableKillSwitch.
+func enableKillSwitch(vpnName string, iface string, servers []string) error {
+	if iface == "" {
+		return fmt.Errorf("no VPN interface to allow traffic through")
+	}
+	if !killSwitchAvailable() {
+		return fmt.Errorf("nft is not installed")
+	}
+	
+	killSwitchMu.Lock()
+	defer killSwitchMu.Unlock()
+	if killSwitchActive {
+		runSudo("nft", "delete", "table", "inet", killSwitchTable)
+	}
+	if err := loadKillSwitchRules(killSwitchRules(iface, servers)); err != nil {
+		return err
+	}
+	if !killSwitchActive {
+		killSwitchStop = make(chan struct{})
+		go removeKillSwitchOnSignal(killSwitchStop)
+	}
+	killSwitchActive, killSwitchVPN = true, vpnName
+	return nil
+}
+
+// loadKillSwitchRules hands the rules to nft in a file rather than on stdin,
+// which runSudo may need for the sudo password.
+func loadKillSwitchRules(rules string) error {
+	file, err := os.CreateTemp("", "charmvpn-killswitch-*.nft")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(rules); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	_, err = runSudo("nft", "-f", file.Name())
+	return err
+}
+
+// disableKillSwitch removes the kill switch rules. Unless sudoPrompt, as on a
+// signal with no terminal to ask on, sudo -n is used and a failure names the
+// command that removes the rules by hand.
+func disableKillSwitch(sudoPrompt bool) error {
+	killSwitchMu.Lock()
+	defer killSwitchMu.Unlock()
+	if !killSwitchActive {
+		return nil
+	}
+	sudo := runSudoNoPrompt
+	if sudoPrompt {
+		sudo = runSudo
+	}
+	if _, err := sudo("nft", "delete", "table", "inet", killSwitchTable); err != nil {
+		return fmt.Errorf("%w; remove them with: sudo nft delete table inet %s", err, killSwitchTable)
+	}
+	close(killSwitchStop)
+	killSwitchActive, killSwitchVPN = false, ""
+	return nil
+}
+
+// killSwitchOn reports whether this charmvpn has the kill switch on.
+func killSwitchOn() bool {
+	killSwitchMu.Lock()
+	defer killSwitchMu.Unlock()
+	return killSwitchActive
+}
+
+// killSwitchProtects reports whether the kill switch is on for vpnName.
+func killSwitchProtects(vpnName string) bool {
+	killSwitchMu.Lock()
+	defer killSwitchMu.Unlock()
+	return killSwitchActive && killSwitchVPN == vpnName
+}
+
+// disableKillSwitchFor removes the kill switch if it protects vpnName, leaving
+// it in place when another VPN disconnects.
+func disableKillSwitchFor(vpnName string, sudoPrompt bool) error {
+	if !killSwitchProtects(vpnName) {
+		return nil
+	}
+	return disableKillSwitch(sudoPrompt)
+}
+
+// removeKillSwitchOnSignal removes the rules when charmvpn is terminated. A
+// Ctrl-C is left alone while a loop such as "Keep VPN connected" handles it to
+// return to the menu.
+func removeKillSwitchOnSignal(stop chan struct{}) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(signals)
+	
+	for {
+		select {
+			case sig := <-signals:
+				if sig == syscall.SIGINT && interruptHandled() {
+					continue
+				}
+				if err := disableKillSwitch(false); err != nil {
+					fmt.Fprintln(os.Stderr, "Failed to remove kill switch rules:", err)
+				}
+				os.Exit(130)
+			case <-stop:
+				return
+		}
+	}
+}
+
+func connectKillSwitch(vpnName string) (string, error) {
+	if err := enableKillSwitch(vpnName, vpnInterface(vpnName), vpnServerAddresses(vpnName)); err != nil {
+		return "", err
+	}
+	return "Kill switch enabled: traffic is blocked outside " + vpnName, nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestKillSwitchStaysForOtherVPNs(t *testing.T) {
+	killSwitchActive, killSwitchVPN = true, "Work"
+	defer func() { killSwitchActive, killSwitchVPN = false, "" }()
+	
+	if err := disableKillSwitchFor("Tokyo", false); err != nil {
+		t.Fatal(err)
+	}
+	if !killSwitchActive || killSwitchVPN != "Work" {
+		t.Errorf("disconnecting Tokyo removed the kill switch protecting Work")
+	}
+}
+
+func TestKillSwitchRemovedWithoutPromptOnSignal(t *testing.T) {
+	fake := useFakeRunner(t, nil)
+	killSwitchActive, killSwitchVPN, killSwitchStop = true, "Work", make(chan struct{})
+	defer func() { killSwitchActive, killSwitchVPN = false, "" }()
+	
+	if err := disableKillSwitch(false); err != nil {
+		t.Fatal(err)
+	}
+	want := "sudo -n nft delete table inet " + killSwitchTable
+	if len(fake.commands) != 1 || strings.Join(fake.commands[0].args, " ") != want {
+		t.Errorf("commands = %v, want only %q", fake.commands, want)
+	}
+}
+
+func TestNotifyInterruptOwnsCtrlC(t *testing.T) {
+	if interruptHandled() {
+		t.Fatal("Ctrl-C handled before notifyInterrupt")
+	}
+	_, stop := notifyInterrupt(os.Interrupt)
+	if !interruptHandled() {
+		t.Error("Ctrl-C not handled during notifyInterrupt")
+	}
+	stop()
+	stop()
+	if interruptHandled() {
+		t.Error("Ctrl-C still handled after stop")
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// activityLogMaxSize is the size at which activity.log is moved aside to
+// activity.log.1 and a fresh log is started.
+const activityLogMaxSize = 1 << 20
+
+var (
+	verbose       bool
+	activityLogMu sync.Mutex
+)
+
+func logVerbose(format string, args ...any) {
+	if !verbose {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "charmvpn: "+format+"\n", args...)
+}
+
+// logWarning is logVerbose for problems the user should hear about even
+// without --verbose. It writes to stderr so it doesn't mix with --json output.
+func logWarning(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "charmvpn: "+format+"\n", args...)
+}
+
+const redacted = "***"
+
+var secretKeyPattern = regexp.MustCompile(`(?i)secret|password|passwd|psk|private-key|token`)
+
+// isSecretKey reports whether a setting or key=value key carries a secret.
+// Flags about secrets and nmcli's passwd-file argument don't.
+func isSecretKey(key string) bool {
+	key = strings.TrimLeft(key, "+-")
+	return secretKeyPattern.MatchString(key) && !strings.HasSuffix(key, "-flags") && key != "passwd-file"
+}
+
+// redactArgs masks the secrets in command arguments for logging: values of
+// key=value arguments with a secret key, and the argument after a secret
+// setting such as "vpn.secrets" or "wireguard.private-key".
+func redactArgs(args []string) []string {
+	masked := make([]string, len(args))
+	hideNext := false
+	for i, arg := range args {
+		key, _, isPair := strings.Cut(arg, "=")
+		switch {
+			case hideNext:
+				masked[i] = redacted
+				if isPair {
+					pairs := strings.Split(arg, ",")
+					for j, pair := range pairs {
+						key, _, _ := strings.Cut(pair, "=")
+						pairs[j] = key + "=" + redacted
+					}
+					masked[i] = strings.Join(pairs, ",")
+				}
+				hideNext = false
+			case isPair && isSecretKey(key):
+				masked[i] = key + "=" + redacted
+			default:
+				masked[i] = arg
+				hideNext = !isPair && isSecretKey(arg)
+		}
+	}
+	return masked
+}
+
+func exitStatus(err error) string {
+	var exitErr *exec.ExitError
+	switch {
+		case err == nil:
+			return "exit status 0"
+		case errors.As(err, &exitErr):
+			return exitErr.Error()
+	}
+	return err.Error()
+}
+
+// logActivity appends an action and its outcome to activity.log in the state
+// directory. Failing to log is reported with --verbose but otherwise ignored.
+func logActivity(action string, target string, err error) {
+	if dryRun {
+		return
+	}
+	if logErr := writeActivity(action, target, err); logErr != nil {
+		logVerbose("could not write activity log: %v", logErr)
+	}
+}
+
+func writeActivity(action string, target string, actionErr error) error {
+	dir, err := stateDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	
+	activityLogMu.Lock()
+	defer activityLogMu.Unlock()
+	
+	path := filepath.Join(dir, "activity.log")
+	if info, err := os.Stat(path); err == nil && info.Size() > activityLogMaxSize {
+		os.Rename(path, path+".1")
+	}
+	
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	
+	result := "ok"
+	if actionErr != nil {
+		result = "error: " + strings.ReplaceAll(strings.TrimSpace(actionErr.Error()), "\n", " ")
+	}
+	_, err = fmt.Fprintf(file, "%s\t%s\t%s\t%s\n", time.Now().Format(time.RFC3339), action, target, result)
+	return err
+}
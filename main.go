@@ -1,313 +1,2511 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
-	"os/user"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
 )
 
 type Action string
 
 const (
-	Connect      Action = "Connect to VPN"
-	Disconnect   Action = "Disconnect from VPN"
-	ListVPNs     Action = "List available VPNs"
-	Status       Action = "Show VPN status"
-	AddVPN       Action = "Add VPN"
-	RemoveVPN    Action = "Remove VPN"
-	ExportVPN    Action = "Export VPN config"
-	Exit         Action = "Exit"
+	Connect       Action = "Connect to VPN"
+	Disconnect    Action = "Disconnect from VPN"
+	Reset         Action = "Emergency reset"
+	RestartVPN    Action = "Restart VPN connection"
+	ListVPNs      Action = "List available VPNs"
+	Status        Action = "Show VPN status"
+	QuickStatus   Action = "Quick status"
+	AvailableVPNs Action = "Import from config directory"
+	AddVPN        Action = "Add VPN"
+	EditVPN       Action = "Edit VPN"
+	SetDNS        Action = "Set DNS servers"
+	SetMTU        Action = "Set MTU"
+	SplitTunnel   Action = "Split tunnel routes"
+	RenameVPN     Action = "Rename VPN"
+	CloneVPN      Action = "Clone VPN"
+	RemoveVPN     Action = "Remove VPN"
+	UndoRemove    Action = "Undo last remove"
+	ExportVPN     Action = "Export VPN config"
+	ExportAll     Action = "Export all VPNs"
+	QRCode        Action = "Show WireGuard QR code"
+	FavoriteVPN   Action = "Add favorite"
+	UnfavoriteVPN Action = "Remove favorite"
+	TagVPN        Action = "Tag VPN"
+	ForgetSecret  Action = "Forget saved password"
+	Autoconnect   Action = "Toggle autoconnect"
+	Priority      Action = "Set autoconnect priority"
+	Reconnect     Action = "Keep VPN connected"
+	Rotate        Action = "Rotate between VPNs"
+	Traffic       Action = "Live traffic"
+	Usage         Action = "Show data usage"
+	Benchmark     Action = "Benchmark VPNs"
+	TestVPN       Action = "Test VPN connection"
+	AuditVPNs     Action = "Check VPN profiles"
+	Overrides     Action = "Show overrides"
+	DryRun        Action = "Toggle dry run"
+	Dashboard     Action = "Dashboard"
+	Exit          Action = "Exit"
+
+	// Reached through main menu shortcuts only.
+	ConnectLast   Action = "Connect to last VPN"
+	DisconnectAll Action = "Disconnect all VPNs"
+)
+
+var menuActions = []Action{
+	Dashboard,
+	Connect,
+	Disconnect,
+	RestartVPN,
+	Reset,
+	ListVPNs,
+	Status,
+	QuickStatus,
+	AddVPN,
+	AvailableVPNs,
+	EditVPN,
+	SetDNS,
+	SetMTU,
+	SplitTunnel,
+	RenameVPN,
+	CloneVPN,
+	RemoveVPN,
+	UndoRemove,
+	ExportVPN,
+	ExportAll,
+	QRCode,
+	FavoriteVPN,
+	UnfavoriteVPN,
+	TagVPN,
+	ForgetSecret,
+	Autoconnect,
+	Priority,
+	Reconnect,
+	Rotate,
+	Traffic,
+	Usage,
+	Benchmark,
+	TestVPN,
+	AuditVPNs,
+	Overrides,
+	DryRun,
+	Exit,
+}
+
+// nmcliActions rely on NetworkManager connection settings and are hidden with
+// other backends.
+var nmcliActions = map[Action]bool{
+	EditVPN:     true,
+	RenameVPN:   true,
+	SetDNS:      true,
+	SetMTU:      true,
+	SplitTunnel: true,
+	CloneVPN:    true,
+	RemoveVPN:   true,
+	UndoRemove:  true,
+	ExportVPN:   true,
+	ExportAll:   true,
+	Autoconnect: true,
+	Priority:    true,
+	Benchmark:   true,
+	TestVPN:     true,
+	AuditVPNs:   true,
+	Overrides:   true,
+}
+
+// tuiActions draw full-screen views and are hidden in --plain mode.
+var tuiActions = map[Action]bool{
+	Dashboard: true,
+	Traffic:   true,
+}
+
+func menuOptions() []huh.Option[Action] {
+	var options []huh.Option[Action]
+	for _, action := range menuActions {
+		if nmcliActions[action] && !usingNmcli() {
+			continue
+		}
+		if tuiActions[action] && plain {
+			continue
+		}
+		options = append(options, huh.NewOption[Action](string(action), action))
+	}
+	return options
+}
+
+const (
+	nmRetryAttempts = 5
+	nmRetryDelay    = 2 * time.Second
 )
 
-func executeCommand(command string, args ...string) string {
-	cmd := exec.Command(command, args...)
-	output, err := cmd.CombinedOutput()
+var nmUnavailableMessages = []string{
+	"NetworkManager is not running",
+	"Could not create NMClient object",
+	"org.freedesktop.DBus.Error.ServiceUnknown",
+	"org.freedesktop.DBus.Error.NoReply",
+}
+
+func isNMUnavailable(output string) bool {
+	for _, msg := range nmUnavailableMessages {
+		if strings.Contains(output, msg) {
+			return true
+		}
+	}
+	return false
+}
+
+// menuStatus summarizes the active VPNs for the main menu. It gives up after a
+// short wait so a slow nmcli never holds up the menu.
+func menuStatus() string {
+	result := make(chan string, 1)
+	go func() {
+		active := getActiveVPNs()
+		status := "Not connected"
+		if len(active) > 0 {
+			labels := make([]string, len(active))
+			for i, vpn := range active {
+				labels[i] = vpnLabel(vpn)
+			}
+			status = "Connected: " + strings.Join(labels, ", ")
+		}
+		if dryRun {
+			status += "\n" + dryRunStyle.Render("Dry run: changes are printed, not made")
+		}
+		if suggestion := wifiSuggestion(active); suggestion != "" {
+			status += "\n" + suggestion
+		}
+		result <- status
+	}()
+	
+	select {
+		case status := <-result:
+			return status
+		case <-time.After(500 * time.Millisecond):
+			return "Connection status unknown"
+	}
+}
+
+func checkNetworkManager() error {
+	if _, err := exec.LookPath("nmcli"); err != nil {
+		return errors.New("nmcli was not found in $PATH.\n" +
+			"charmvpn manages VPNs through NetworkManager; install it (the package is usually\n" +
+			"called network-manager or NetworkManager) along with the VPN plugins you need,\n" +
+			"e.g. network-manager-openvpn.")
+	}
+	return nil
+}
+
+func networkManagerRunning() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	output, err := runCommand(ctx, "", "nmcli", "-t", "-f", "RUNNING", "general")
+	return err == nil && strings.TrimSpace(output) == "running"
+}
+
+func isNmcliCommand(command string, args []string) bool {
+	return command == "nmcli" || (command == "sudo" && len(args) > 0 && args[0] == "nmcli")
+}
+
+var ErrTimeout = errors.New("timed out")
+
+type CommandError struct {
+	Command string
+	Output  string
+	Err     error
+}
+
+func (e *CommandError) Error() string {
+	if errors.Is(e.Err, ErrTimeout) {
+		return fmt.Sprintf("%s timed out", e.Command)
+	}
+	if msg := strings.TrimSpace(e.Output); msg != "" {
+		return strings.TrimPrefix(msg, "Error: ")
+	}
+	return fmt.Sprintf("%s: %s", e.Command, e.Err)
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+func timeoutError(err error, operation string, timeout time.Duration) error {
+	if errors.Is(err, ErrTimeout) {
+		return fmt.Errorf("%s %w after %s", operation, ErrTimeout, timeout)
+	}
+	return err
+}
+
+func runCommand(ctx context.Context, stdin string, command string, args ...string) (string, error) {
+	logVerbose("running %s", strings.Join(append([]string{command}, redactArgs(args)...), " "))
+	start := time.Now()
+	output, err := runner.Run(ctx, stdin, command, args...)
+	logVerbose("%s finished after %s: %s", command, time.Since(start).Round(time.Millisecond), exitStatus(err))
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return output, &CommandError{Command: command, Output: output, Err: ErrTimeout}
+	}
 	if err != nil {
-		return fmt.Sprintf("Error: %s\n%s", err, output)
+		return output, &CommandError{Command: command, Output: output, Err: err}
 	}
-	return string(output)
+	return output, nil
 }
 
-func listVPNs() string {
-	output := executeCommand("nmcli", "-t", "-f", "NAME,TYPE", "connection", "show")
-	lines := strings.Split(strings.TrimSpace(output), "\n")
+func executeCommand(command string, args ...string) (string, error) {
+	return executeCommandContext(baseContext(), "", command, args...)
+}
+
+// executeCommandStdout runs a command for its stdout alone. Its stderr only
+// goes into the error, when the runner keeps the two apart.
+func executeCommandStdout(command string, args ...string) (string, error) {
+	stdoutRunner, ok := runner.(StdoutRunner)
+	if !ok {
+		return executeCommand(command, args...)
+	}
+	logVerbose("running %s", strings.Join(append([]string{command}, redactArgs(args)...), " "))
+	stdout, stderr, err := stdoutRunner.RunStdout(baseContext(), "", command, args...)
+	if err != nil {
+		return "", &CommandError{Command: command, Output: stderr, Err: err}
+	}
+	return stdout, nil
+}
+
+func executeCommandTimeout(timeout time.Duration, command string, args ...string) (string, error) {
+	return executeCommandInput(timeout, "", command, args...)
+}
+
+func executeCommandInput(timeout time.Duration, stdin string, command string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(baseContext(), timeout)
+	defer cancel()
+	return executeCommandContext(ctx, stdin, command, args...)
+}
+
+func executeCommandContext(ctx context.Context, stdin string, command string, args ...string) (string, error) {
+	output, err := runCommand(ctx, stdin, command, args...)
+	if !isNmcliCommand(command, args) {
+		return output, err
+	}
 	
-	var vpns []string
-	for _, line := range lines {
-		if strings.Contains(line, ":vpn") {
-			vpn := strings.Split(line, ":")[0]
-			vpns = append(vpns, vpn)
+	for attempt := 1; attempt <= nmRetryAttempts && err != nil && isNMUnavailable(output) && ctx.Err() == nil; attempt++ {
+		if attempt == 1 {
+			logWarning("NetworkManager is not available, waiting for it to come back...")
 		}
+		time.Sleep(nmRetryDelay)
+		output, err = runCommand(ctx, stdin, command, args...)
+	}
+	if err != nil && isNMUnavailable(output) {
+		return output, fmt.Errorf("NetworkManager did not become available after %d retries: %w", nmRetryAttempts, err)
+	}
+	return output, err
+}
+
+func listVPNs() (string, error) {
+	vpns, err := fetchVPNList()
+	if err != nil {
+		return "", err
 	}
 	
 	if len(vpns) == 0 {
-		return "No VPN connections found"
+		return "No VPN connections found", nil
 	}
 	
+	autoconnect := autoconnectEnabled()
+	types := vpnTypes()
 	var result strings.Builder
-	result.WriteString("Available VPN connections:\n")
-	for i, vpn := range vpns {
-		result.WriteString(fmt.Sprintf("%d. %s\n", i+1, vpn))
+	result.WriteString("Available VPN connections (* = autoconnect):\n")
+	n := 0
+	for _, group := range groupVPNs(vpns, types) {
+		result.WriteString("\n" + group.Name + "\n")
+		for _, vpn := range group.VPNs {
+			n++
+			label := vpnLabel(vpn)
+			if types[vpn] != "" {
+				label += " (" + types[vpn] + ")"
+			}
+			result.WriteString(fmt.Sprintf("  %d. %s%s\n", n, label, autoconnectMarker(autoconnect[vpn])))
+		}
 	}
 	
-	return result.String()
+	return result.String(), nil
 }
 
-func getVPNList() []string {
-	output := executeCommand("nmcli", "-t", "-f", "NAME,TYPE", "connection", "show")
-	lines := strings.Split(strings.TrimSpace(output), "\n")
-	
+func fetchVPNList() ([]string, error) {
+	list, err := cachedVPNList()
+	if err != nil {
+		return nil, err
+	}
+	return menuVPNList(list), nil
+}
+
+// menuVPNList returns the identifiers of list as menus show them: each once,
+// favorites first and otherwise by name.
+func menuVPNList(list []VPN) []string {
+	seen := map[string]bool{}
 	var vpns []string
-	for _, line := range lines {
-		if strings.Contains(line, ":vpn") {
-			vpn := strings.Split(line, ":")[0]
-			vpns = append(vpns, vpn)
+	for _, id := range vpnIDs(list) {
+		if !seen[id] {
+			seen[id] = true
+			vpns = append(vpns, id)
 		}
 	}
+	sort.Slice(vpns, func(i, j int) bool {
+		return strings.ToLower(vpnDisplayName(vpns[i])) < strings.ToLower(vpnDisplayName(vpns[j]))
+	})
+	sortFavoritesFirst(vpns)
 	return vpns
 }
 
-func connectVPN(vpnName string) string {
-	return executeCommand("nmcli", "connection", "up", vpnName)
+func getVPNList() []string {
+	vpns, _ := fetchVPNList()
+	return vpns
 }
 
-func getActiveVPNs() []string {
-	output := executeCommand("nmcli", "-t", "-f", "NAME,TYPE", "connection", "show", "--active")
-	lines := strings.Split(strings.TrimSpace(output), "\n")
+// connectionExists checks a fresh VPN list for vpnName, since menu options can
+// go stale when connections are removed outside charmvpn.
+func connectionExists(vpnName string) (bool, error) {
+	list, err := backend.List()
+	if err != nil {
+		return false, err
+	}
+	for _, vpn := range vpnIDs(list) {
+		if vpn == vpnName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func requireConnection(vpnName string) error {
+	exists, err := connectionExists(vpnName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("connection %s no longer exists", vpnName)
+	}
+	return nil
+}
+
+var errorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+
+func printResult(output string, err error) {
+	if output != "" {
+		fmt.Println(simulated(output))
+	}
+	if err != nil {
+		fmt.Println(errorStyle.Render("Error: " + err.Error()))
+	}
+}
+
+func connectVPN(vpnName string) (string, error) {
+	return ignoreAlreadyConnected(connectVPNWithSecret(vpnName, connectOptions{}, nil))
+}
+
+// ErrAlreadyConnected is returned by connectVPNWithSecret, along with a
+// message for the user, when the VPN is up and force isn't set.
+var ErrAlreadyConnected = errors.New("already connected")
+
+// ignoreAlreadyConnected treats connecting a VPN that is up as success, for
+// callers that only care about the VPN being connected afterwards.
+func ignoreAlreadyConnected(output string, err error) (string, error) {
+	if errors.Is(err, ErrAlreadyConnected) {
+		return output, nil
+	}
+	return output, err
+}
+
+func alreadyConnectedMessage(vpnName string) string {
+	return fmt.Sprintf("%s is already connected", vpnDisplayName(vpnName))
+}
+
+// connectOptions change how a VPN is connected. device binds the connection
+// to a network device; force re-activates a VPN that is already connected.
+type connectOptions struct {
+	device string
+	force  bool
+}
+
+func connectVPNWithSecret(vpnName string, opts connectOptions, askSecret func() (string, bool)) (output string, err error) {
+	if !opts.force && slices.Contains(getActiveVPNs(), vpnName) {
+		return alreadyConnectedMessage(vpnName), ErrAlreadyConnected
+	}
+	device := opts.device
+	
+	defer func() {
+		invalidateCache()
+		logActivity("connect", vpnName, err)
+	}()
+	
+	if _, err := runEventHook(EventPreConnect, vpnName); err != nil {
+		return "", fmt.Errorf("%s hook failed, not connecting: %w", EventPreConnect, err)
+	}
+	
+	secret, err := storedSecret(vpnName)
+	if err != nil {
+		return "", withEventHookWarning(err, EventConnectFail, vpnName)
+	}
+	sb, ok := backend.(secretBackend)
+	if !ok && device != "" {
+		return "", fmt.Errorf("the %s backend can't connect through a chosen device", backend.Name())
+	}
+	if ok && askSecret != nil {
+		// Only interactive connects spin; the others may run inside another TUI.
+		sb = spinningBackend{sb}
+	}
+	if ok && otpMode(vpnName) != "" {
+		// One-time codes can't be reused, so these attempts are never retried.
+		output, err = connectWithOTP(sb, vpnName, device, secret, askSecret)
+	} else {
+		var attempts int
+		output, attempts, err = retryConnect(vpnName, func() (string, error) {
+			if !ok {
+				return backend.Connect(vpnName)
+			}
+			output, err := sb.ConnectWithSecret(vpnName, secret, "", device)
+			if err == nil || !needsSecrets(output) {
+				return output, err
+			}
+			if askSecret == nil {
+				return output, fmt.Errorf("%s needs a password: connect from a terminal or configure a pass entry", vpnName)
+			}
+			var entered bool
+			if secret, entered = askSecret(); !entered {
+				return output, err
+			}
+			return sb.ConnectWithSecret(vpnName, secret, "", device)
+		})
+		if err == nil && attempts > 1 {
+			output += fmt.Sprintf("Connected after %d attempts\n", attempts)
+		}
+	}
+	if err != nil {
+		return "", withEventHookWarning(err, EventConnectFail, vpnName)
+	}
+	recordConnected(vpnName)
+	notifyEvent(fmt.Sprintf("Connected to %s", vpnName))
 	
+	detectExitCountry(vpnName)
+	countryCheck, ok := verifyExitCountry(vpnName)
+	if !ok {
+		err := fmt.Errorf("exit country mismatch\n%s", strings.TrimSpace(countryCheck))
+		return "", withEventHookWarning(err, EventConnectFail, vpnName)
+	}
+	rememberLastConnected(vpnName)
+	return output + countryCheck + eventHookWarning(EventConnect, vpnName) + vpnHookOutput(vpnName, PhaseUp), nil
+}
+
+// storedSecret returns the password of vpnName from its pass entry or the
+// keyring, or "" when neither has one.
+func storedSecret(vpnName string) (string, error) {
+	if entry := passEntry(vpnName); entry != "" {
+		return passSecret(entry)
+	}
+	return keyringSecret(vpnName), nil
+}
+
+func getActiveVPNs() []string {
+	list := getVPNs()
 	var vpns []string
-	for _, line := range lines {
-		if strings.Contains(line, ":vpn") {
-			vpn := strings.Split(line, ":")[0]
-			vpns = append(vpns, vpn)
+	for i, id := range vpnIDs(list) {
+		if list[i].Active {
+			vpns = append(vpns, id)
 		}
 	}
 	return vpns
 }
 
-func disconnectVPN() string {
-	vpns := getActiveVPNs()
+// disconnectAllOption is the "All active VPNs" entry of the disconnect picker.
+const disconnectAllOption = "\x00all"
+
+func disconnectVPN() (string, error) {
+	return disconnectVPNs(getActiveVPNs())
+}
+
+type DisconnectResult struct {
+	Name   string
+	Output string
+	Err    error
+}
+
+// disconnectEach disconnects every VPN in vpns, carrying on past failures.
+func disconnectEach(vpns []string) []DisconnectResult {
+	results := make([]DisconnectResult, 0, len(vpns))
+	for _, vpn := range vpns {
+		output, err := disconnectVPNByName(vpn, true)
+		results = append(results, DisconnectResult{Name: vpn, Output: output, Err: err})
+	}
+	return results
+}
+
+func formatDisconnectResults(results []DisconnectResult) (string, error) {
+	var result strings.Builder
+	var failed []string
+	for _, r := range results {
+		output := r.Output
+		if r.Err != nil {
+			failed = append(failed, r.Name)
+			output = r.Err.Error()
+		}
+		result.WriteString(fmt.Sprintf("Disconnecting %s: %s\n", r.Name, output))
+	}
+	if len(failed) > 0 {
+		return result.String(), fmt.Errorf("could not disconnect %d of %d VPNs: %s", len(failed), len(results), strings.Join(failed, ", "))
+	}
+	return result.String(), nil
+}
+
+func disconnectVPNs(vpns []string) (string, error) {
 	if len(vpns) == 0 {
-		return "No active VPN connections found"
+		return "No active VPN connections found", nil
 	}
+	return formatDisconnectResults(disconnectEach(vpns))
+}
+
+// confirmDisconnect lists the connections that are about to drop and asks
+// before going ahead.
+func confirmDisconnect(vpns []string) bool {
+	if len(vpns) == 0 {
+		return true
+	}
+	title := fmt.Sprintf("Disconnect %s?", vpns[0])
+	if len(vpns) > 1 {
+		title = fmt.Sprintf("Disconnect these %d VPNs?\n  %s", len(vpns), strings.Join(vpns, "\n  "))
+	}
+	return confirm(title, "")
+}
+
+// disconnectVPNByName disconnects vpnName. sudoPrompt says whether a form may
+// ask for the sudo password to remove its kill switch, which it can't while
+// another program such as the dashboard owns the terminal.
+func disconnectVPNByName(vpnName string, sudoPrompt bool) (string, error) {
+	sampleUsage(vpnName)
+	output, err := backend.Disconnect(vpnName)
+	invalidateCache()
+	logActivity("disconnect", vpnName, err)
+	if err != nil {
+		return "", err
+	}
+	forgetConnected(vpnName)
+	endUsageSession(vpnName)
+	notifyEvent(fmt.Sprintf("Disconnected from %s", vpnName))
+	if err := disableKillSwitchFor(vpnName, sudoPrompt); err != nil {
+		output += "Failed to remove kill switch rules: " + err.Error() + "\n"
+	}
+	return output + eventHookWarning(EventDisconnect, vpnName) + vpnHookOutput(vpnName, PhaseDown), nil
+}
+
+// vpnStatus prints the active VPNs, limited to those tagged tag unless it is
+// empty.
+func vpnStatus(tag string) (string, error) {
+	statuses, statusErr := backend.Status()
+	if tag != "" {
+		var tagged []VPNStatus
+		for _, status := range statuses {
+			if hasTag(status.Name, tag) {
+				tagged = append(tagged, status)
+			}
+		}
+		statuses = tagged
+	}
+	if len(statuses) == 0 {
+		if statusErr != nil {
+			return "", statusErr
+		}
+		return "No active VPN connections", nil
+	}
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return isFavorite(statuses[i].Name) && !isFavorite(statuses[j].Name)
+	})
 	
-	var result strings.Builder
-	for _, vpn := range vpns {
-		output := executeCommand("nmcli", "connection", "down", vpn)
-		result.WriteString(fmt.Sprintf("Disconnecting %s: %s\n", vpn, output))
+	return statusTable(statuses, vpnTypes()), statusErr
+}
+
+func detectVPNType(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+		case ".ovpn":
+			return "openvpn", nil
+		case ".conf":
+			return peekVPNType(path)
 	}
-	return result.String()
+	return "", fmt.Errorf("unrecognized file extension %q, expected .ovpn or .conf", filepath.Ext(path))
 }
 
-func vpnStatus() string {
-	activeVpns := getActiveVPNs()
-	if len(activeVpns) == 0 {
-		return "No active VPN connections"
+func peekVPNType(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
 	}
+	defer file.Close()
 	
-	var result strings.Builder
-	result.WriteString("Active VPN connections:\n")
+	scanner := bufio.NewScanner(file)
+	for lines := 0; scanner.Scan() && lines < 50; lines++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+			case line == "[Interface]" || line == "[Peer]":
+				return "wireguard", nil
+			case line == "client" || strings.HasPrefix(line, "remote ") || strings.HasPrefix(line, "dev tun"):
+				return "openvpn", nil
+			case strings.HasPrefix(line, "conn ") || strings.HasPrefix(line, "keyexchange="):
+				return "strongswan", nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("could not tell whether %s is a WireGuard, OpenVPN or strongSwan config", filepath.Base(path))
+}
+
+// resolveImportPath expands a leading ~ and makes sure the path is a regular
+// file we can read, so typos don't surface as nmcli errors.
+func resolveImportPath(path string) (string, error) {
+	path, err := expandHome(strings.TrimSpace(path))
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("%s does not exist", path)
+	}
+	if err != nil {
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("%s is not a regular file", path)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("%s is not readable: %w", path, errors.Unwrap(err))
+	}
+	file.Close()
+	return path, nil
+}
+
+func addVPN(vpnFile string) (string, error) {
+	vpnFile, err := resolveImportPath(vpnFile)
+	if err != nil {
+		return "", err
+	}
+	output, err := backend.Import(vpnFile)
+	invalidateCache()
+	logActivity("import", vpnFile, err)
+	return output, err
+}
+
+func importConnection(vpnType string, path string) (string, error) {
+	timeout := connectTimeout("")
+	output, err := executeCommandTimeout(timeout, "nmcli", "connection", "import", "type", vpnType, "file", path)
+	return output, timeoutError(err, "import", timeout)
+}
+
+// removeVPN deletes vpnName after backing it up for "Undo last remove". A VPN
+// that can't be backed up is only removed once confirm agrees to losing it.
+func removeVPN(vpnName string, confirm func(title string, description string) bool) (string, error) {
+	backup, backupErr := backupVPN(vpnName)
+	if backupErr != nil && !confirm(
+		fmt.Sprintf("Could not back up %s. Remove it anyway?", vpnName),
+		fmt.Sprintf("It can't be restored with Undo last remove: %s", backupErr)) {
+		return "", fmt.Errorf("not removing %s, which could not be backed up: %w", vpnName, backupErr)
+	}
+	output, err := executeCommand("nmcli", "connection", "delete", vpnName)
+	invalidateCache()
+	logActivity("remove", vpnName, err)
+	if err != nil {
+		if backup != "" {
+			os.RemoveAll(filepath.Dir(backup))
+		}
+		return output, err
+	}
+	return output, nil
+}
+
+func removeVPNs(vpns []string, confirm func(title string, description string) bool) (string, error) {
+	if len(vpns) == 1 {
+		return removeVPN(vpns[0], confirm)
+	}
 	
-	for _, vpn := range activeVpns {
-		details := executeCommand("nmcli", "connection", "show", vpn)
-		result.WriteString(fmt.Sprintf("--- %s ---\n%s\n", vpn, details))
+	var result strings.Builder
+	var failed []string
+	for _, vpn := range vpns {
+		if _, err := removeVPN(vpn, confirm); err != nil {
+			failed = append(failed, vpn)
+			result.WriteString(fmt.Sprintf("Removing %s: %s\n", vpn, err))
+			continue
+		}
+		result.WriteString(fmt.Sprintf("Removing %s: done\n", vpn))
+	}
+	if len(failed) > 0 {
+		return result.String(), fmt.Errorf("could not remove %s", strings.Join(failed, ", "))
+	}
+	return result.String(), nil
+}
+
+func validateNewVPNName(name string, existing []string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+	for _, vpn := range existing {
+		if vpn == name {
+			return fmt.Errorf("a VPN named %s already exists", name)
+		}
+	}
+	return nil
+}
+
+func renameVPN(oldName string, newName string) (string, error) {
+	newName = strings.TrimSpace(newName)
+	_, err := executeCommand("nmcli", "connection", "modify", oldName, "connection.id", newName)
+	invalidateCache()
+	logActivity("rename", oldName+" -> "+newName, err)
+	if err != nil {
+		return "", err
+	}
+	renameState(oldName, newName)
+	return fmt.Sprintf("Renamed %s to %s", oldName, newName), nil
+}
+
+func cloneVPN(source string, newName string) error {
+	_, err := executeCommand("nmcli", "connection", "clone", source, strings.TrimSpace(newName))
+	invalidateCache()
+	logActivity("clone", source+" -> "+strings.TrimSpace(newName), err)
+	return err
+}
+
+var permissionDeniedMessages = []string{
+	"Insufficient privileges",
+	"not authorized",
+	"Not authorized",
+	"Permission denied",
+	"permission denied",
+}
+
+func isPermissionDenied(output string) bool {
+	for _, msg := range permissionDeniedMessages {
+		if strings.Contains(output, msg) {
+			return true
+		}
+	}
+	return false
+}
+
+func connectionVPNType(vpnName string) (string, error) {
+	output, err := executeCommand("nmcli", "-g", "connection.type,vpn.service-type", "connection", "show", vpnName)
+	if err != nil {
+		return "", err
+	}
+	lines := outputLines(output)
+	if len(lines) == 0 {
+		return "", fmt.Errorf("could not determine the type of %s", vpnName)
+	}
+	if lines[0] == "wireguard" || len(lines) < 2 {
+		return lines[0], nil
+	}
+	serviceType := lines[1]
+	return serviceType[strings.LastIndex(serviceType, ".")+1:], nil
+}
+
+func exportExtension(vpnType string) string {
+	if vpnType == "openvpn" {
+		return ".ovpn"
+	}
+	return ".conf"
+}
+
+// defaultExportName names the export after the VPN, with path separators
+// replaced so the name can't point into another directory.
+func defaultExportName(vpnName string, vpnType string) string {
+	return strings.ReplaceAll(vpnName, string(filepath.Separator), "-") + exportExtension(vpnType)
+}
+
+// secretMarkers start lines of exported configs that embed keys or
+// credentials: OpenVPN inline blocks and WireGuard keys.
+var secretMarkers = []string{
+	"<key>",
+	"<cert>",
+	"<pkcs12>",
+	"<secret>",
+	"<tls-auth>",
+	"<tls-crypt>",
+	"<tls-crypt-v2>",
+	"<auth-user-pass>",
+	"privatekey",
+	"private-key",
+	"presharedkey",
+	"preshared-key",
+}
+
+func containsSecrets(config string) bool {
+	for _, line := range strings.Split(config, "\n") {
+		line = strings.ToLower(strings.TrimSpace(line))
+		for _, marker := range secretMarkers {
+			if strings.HasPrefix(line, marker) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func exportConfig(vpnName string) (string, error) {
+	output, err := executeCommand("nmcli", "connection", "export", vpnName)
+	if err != nil && isPermissionDenied(output) {
+		logVerbose("export of %s needs privileges, retrying with sudo", vpnName)
+		output, err = runSudo("nmcli", "connection", "export", vpnName)
+	}
+	return output, err
+}
+
+// copyVPNConfig puts the config of vpnName on the clipboard, asking confirm
+// first when it embeds keys or credentials.
+func copyVPNConfig(vpnName string, confirm func(title string, description string) bool) (string, error) {
+	output, err := exportConfig(vpnName)
+	if err != nil {
+		return "", err
+	}
+	if containsSecrets(output) && !confirm(
+		"This config contains private keys or credentials. Copy anyway?",
+		"Clipboard managers may keep it in their history.") {
+		return "Export cancelled", nil
+	}
+	if err := copyToClipboard(output); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Copied the configuration of %s to the clipboard", vpnName), nil
+}
+
+// ensureExportDir makes sure dir exists, creating it if confirm agrees, and
+// that files can be written to it.
+func ensureExportDir(dir string, confirm func(title string, description string) bool) error {
+	info, err := os.Stat(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		if !confirm(fmt.Sprintf("%s does not exist. Create it?", dir), "") {
+			return fmt.Errorf("export directory %s does not exist", dir)
+		}
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return fmt.Errorf("could not create export directory %s: %w", dir, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cannot use export directory %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("export directory %s is not a directory", dir)
 	}
 	
-	return result.String()
+	probe, err := os.CreateTemp(dir, ".charmvpn-*")
+	if err != nil {
+		return fmt.Errorf("export directory %s is not writable", dir)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+	return nil
 }
 
-func addVPN(vpnFile string) string {
-	return executeCommand("nmcli", "connection", "import", "type", "openvpn", "file", vpnFile)
+// resolveSymlinks is filepath.EvalSymlinks for paths whose last elements may
+// not exist yet: the longest existing prefix is resolved and the rest kept.
+func resolveSymlinks(path string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return resolved, err
+	}
+	parent := filepath.Dir(path)
+	if parent == path {
+		return path, nil
+	}
+	resolvedParent, err := resolveSymlinks(parent)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(resolvedParent, filepath.Base(path)), nil
 }
 
-func removeVPN(vpnName string) string {
-	return executeCommand("nmcli", "connection", "delete", vpnName)
+// safeExportPath expands and normalizes a user-entered export path into an
+// absolute one. Unless baseDir is empty, paths outside of it are rejected,
+// including ones that leave it through a symlink.
+func safeExportPath(input string, baseDir string) (string, error) {
+	expanded, err := expandHome(strings.TrimSpace(input))
+	if err != nil {
+		return "", err
+	}
+	path, err := filepath.Abs(filepath.Clean(expanded))
+	if err != nil {
+		return "", err
+	}
+	if baseDir == "" {
+		return path, nil
+	}
+	
+	base, err := filepath.Abs(filepath.Clean(baseDir))
+	if err != nil {
+		return "", err
+	}
+	resolvedBase, err := resolveSymlinks(base)
+	if err != nil {
+		return "", err
+	}
+	resolvedPath, err := resolveSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(resolvedBase, resolvedPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the export directory %s", path, base)
+	}
+	return path, nil
 }
 
-func exportVPN(vpnName string, outputPath string) string {
+// exportVPN writes the config of vpnName to outputPath. confirm is asked
+// before creating a missing directory and before writing a config that embeds
+// keys or credentials.
+func exportVPN(vpnName string, outputPath string, confirm func(title string, description string) bool) (string, error) {
+	vpnType, err := connectionVPNType(vpnName)
+	if err != nil {
+		return "", err
+	}
+	
 	if outputPath == "" {
-		usr, err := user.Current()
+		dir, err := exportDir()
 		if err != nil {
-			return fmt.Sprintf("Error: %s", err)
+			return "", err
 		}
-		outputPath = filepath.Join(usr.HomeDir, vpnName+".ovpn")
+		outputPath = filepath.Join(dir, defaultExportName(vpnName, vpnType))
 	} else {
-		if !strings.HasSuffix(outputPath, ".ovpn") {
-			outputPath = outputPath + ".ovpn"
+		var baseDir string
+		if config.RestrictExports {
+			if baseDir, err = exportDir(); err != nil {
+				return "", err
+			}
 		}
+		if outputPath, err = safeExportPath(outputPath, baseDir); err != nil {
+			return "", err
+		}
+		if ext := strings.ToLower(filepath.Ext(outputPath)); ext != ".ovpn" && ext != ".conf" {
+			outputPath = outputPath + exportExtension(vpnType)
+		}
+	}
+	if err := ensureExportDir(filepath.Dir(outputPath), confirm); err != nil {
+		return "", err
 	}
 	
-	output := executeCommand("sudo", "nmcli", "connection", "export", vpnName)
-	if strings.Contains(output, "Error") {
-		return output
+	output, err := exportConfig(vpnName)
+	if err != nil {
+		return "", err
+	}
+	if containsSecrets(output) && !confirm(
+		"This config contains private keys or credentials. Export anyway?",
+		fmt.Sprintf("Anyone who can read %s can use this connection.", outputPath)) {
+		return "Export cancelled", nil
 	}
 	
-	err := os.WriteFile(outputPath, []byte(output), 0600)
+	err = os.WriteFile(outputPath, []byte(output), 0600)
 	if err != nil {
-		return fmt.Sprintf("Error writing to file: %s", err)
+		return "", fmt.Errorf("writing to file: %w", err)
 	}
 	
-	return fmt.Sprintf("Successfully exported VPN configuration to %s", outputPath)
+	return fmt.Sprintf("Successfully exported VPN configuration to %s", outputPath), nil
 }
 
 func main() {
+	quick := flag.Bool("quick", false, "print a compact one-screen status and exit")
+	batch := flag.String("batch", "", "run the charmvpn commands in `file` and exit")
+	favorites := flag.Bool("favorites", false, "connect a favorite VPN instead of showing the menu")
+	serveAPI := flag.Bool("serve", false, "serve list/status/connect/disconnect as JSON on a Unix socket")
+	socket := flag.String("socket", "", "Unix socket `path` for --serve (default $XDG_RUNTIME_DIR/charmvpn.sock)")
+	setup := flag.Bool("setup", false, "check the tools charmvpn needs, choose backend, export directory and theme, and exit")
+	reset := flag.Bool("reset", false, "disconnect all VPNs, remove kill switch rules, optionally restart NetworkManager and exit")
+	var cli cliOptions
+	flag.StringVar(&cli.connect, "connect", "", "connect the VPN with this `name` and exit")
+	flag.BoolVar(&cli.disconnect, "disconnect", false, "disconnect all active VPNs and exit")
+	flag.BoolVar(&cli.list, "list", false, "list the available VPNs and exit")
+	flag.BoolVar(&cli.status, "status", false, "print the status of active VPNs and exit")
+	flag.BoolVar(&cli.json, "json", false, "print --connect, --list and --status output as JSON")
+	flag.BoolVar(&cli.force, "force", false, "with --connect, re-activate the VPN even if it is already connected")
+	flag.BoolVar(&cli.quiet, "quiet", false, "print only errors, for use in scripts")
+	flag.BoolVar(&dryRun, "dry-run", false, "print the commands that would change something instead of running them")
+	flag.BoolVar(&verbose, "verbose", false, "log details about what charmvpn is doing to stderr")
+	flag.BoolVar(&plain, "plain", false, "line-based prompts and plain output without colors or emoji")
+	flag.BoolVar(&plain, "no-tui", false, "same as --plain")
+	flag.Parse()
+	setupOutput()
+	setDryRun(dryRun)
+	
+	cfg, err := loadConfig()
+	if err != nil {
+		logWarning("error loading config: %v", err)
+	}
+	config, configErr = cfg, err
+	
+	if *setup {
+		if _, err := runSetup(config); err != nil && !errors.Is(err, huh.ErrUserAborted) {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if err == nil && !*quick && !*reset && !cli.requested() && *batch == "" && !*serveAPI {
+		config = offerSetup(config)
+	}
+	
+	if backend, err = selectBackend(config.Backend); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	if formTheme, err = selectTheme(config.Theme); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	if usingNmcli() {
+		if err := checkNetworkManager(); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		if !networkManagerRunning() {
+			fmt.Fprintln(os.Stderr, "Warning: the NetworkManager service does not appear to be running; start it with `systemctl start NetworkManager`.")
+		}
+	}
+	
+	if *quick {
+		fmt.Println(quickStatus())
+		return
+	}
+	if *reset {
+		if err := panicDisconnect(); err != nil {
+			os.Exit(exitCode(err))
+		}
+		return
+	}
+	if cli.requested() {
+		if err := runCLI(cli); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(exitCode(err))
+		}
+		return
+	}
+	if *batch != "" {
+		if err := runBatch(*batch); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(exitCode(err))
+		}
+		return
+	}
+	if *serveAPI {
+		socketPath := *socket
+		if socketPath == "" {
+			if socketPath, err = defaultSocketPath(); err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				os.Exit(1)
+			}
+		}
+		if err := serve(socketPath); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *favorites {
+		if ok, err := connectFavorite(); ok {
+			if err != nil {
+				os.Exit(exitCode(err))
+			}
+			return
+		}
+	}
+	startReminders()
+	startUsageSampler()
+	defer disableKillSwitch(true)
+	autoconnectWiFiVPN()
+	
 	for {
-		var action Action
-		form := huh.NewForm(
-			huh.NewGroup(
-				huh.NewSelect[Action]().
-					Title("Choose an action").
-					Value(&action).
-					Options(
-						huh.NewOption[Action](string(Connect), Connect),
-						huh.NewOption[Action](string(Disconnect), Disconnect),
-						huh.NewOption[Action](string(ListVPNs), ListVPNs),
-						huh.NewOption[Action](string(Status), Status),
-						huh.NewOption[Action](string(AddVPN), AddVPN),
-						huh.NewOption[Action](string(RemoveVPN), RemoveVPN),
-						huh.NewOption[Action](string(ExportVPN), ExportVPN),
-						huh.NewOption[Action](string(Exit), Exit),
-					),
-			),
-		).WithTheme(huh.ThemeCatppuccin())
-		
-		if err := form.Run(); err != nil {
+		action, err := runMainMenu()
+		if errors.Is(err, huh.ErrUserAborted) {
+			return
+		} else if err != nil {
 			fmt.Println("Error:", err)
 			return
 		}
 		
-		switch action {
-			case Connect:
-				vpns := getVPNList()
-				if len(vpns) == 0 {
-					fmt.Println("No VPN connections available")
-					continue
+		if err := runAction(action); errors.Is(err, errExit) {
+			return
+		} else if err != nil {
+			printResult("", err)
+		}
+	}
+}
+
+// errExit is returned by runAction when the user chose to quit.
+var errExit = errors.New("exit")
+
+// runAction carries out a main menu action. Errors the action already showed
+// to the user are not returned.
+func runAction(action Action) error {
+	switch action {
+		case Connect:
+			tag, ok := selectTag()
+			if !ok {
+				return nil
+			}
+			vpns := menuVPNList(filterByTag(getVPNs(), tag))
+			if len(vpns) == 0 {
+				fmt.Println("No VPN connections available")
+				return nil
+			}
+			
+			selectedVPN := lastConnectedVPN(vpns)
+			if _, vpn := wifiVPN(vpns); vpn != "" {
+				selectedVPN = vpn
+			}
+			vpnForm := newForm(
+				newGroup(
+					vpnSelect("Select VPN to connect", &selectedVPN, vpns),
+				),
+			)
+			
+			if err := vpnForm.Run(); err == nil && selectedVPN != "" {
+				if err := requireConnection(selectedVPN); err != nil {
+					printResult("", err)
+					return nil
 				}
+				force := false
+				if slices.Contains(getActiveVPNs(), selectedVPN) {
+					if !confirm(fmt.Sprintf("%s is already connected. Reconnect it anyway?", selectedVPN), "") {
+						return nil
+					}
+					force = true
+				}
+				if !confirmCaptivePortal() {
+					return nil
+				}
+				if overlaps := findSubnetOverlaps(selectedVPN); len(overlaps) > 0 {
+					fmt.Println(warningStyle.Render("Warning: " + selectedVPN + " uses subnets that are already routed:"))
+					for _, overlap := range overlaps {
+						fmt.Println("  " + overlap)
+					}
+					
+					var proceed bool
+					confirmForm := newForm(
+						newGroup(
+							huh.NewConfirm().
+								Title("Connect anyway?").
+								Value(&proceed),
+						),
+					)
+					if err := confirmForm.Run(); err != nil || !proceed {
+						return nil
+					}
+				}
+				var others []string
+				for _, vpn := range getActiveVPNs() {
+					if vpn != selectedVPN {
+						others = append(others, vpn)
+					}
+				}
+				if len(others) > 0 {
+					disconnectFirst := true
+					activeForm := newForm(
+						newGroup(
+							huh.NewConfirm().
+								Title(fmt.Sprintf("%s is already connected. Disconnect it first?", strings.Join(others, ", "))).
+								Description("Keeping both up can route traffic through either tunnel.").
+								Affirmative("Disconnect first").
+								Negative("Keep it").
+								Value(&disconnectFirst),
+						),
+					)
+					if err := activeForm.Run(); err != nil {
+						return nil
+					}
+					if disconnectFirst {
+						printResult(disconnectVPNs(others))
+					}
+				}
+				device, ok := selectDevice()
+				if !ok {
+					return nil
+				}
+				opts := connectOptions{device: device, force: force}
 				
-				var selectedVPN string
-				vpnOptions := make([]huh.Option[string], len(vpns))
-				for i, vpn := range vpns {
-					vpnOptions[i] = huh.NewOption[string](vpn, vpn)
+				var ipBefore IPInfo
+				var ipBeforeErr error
+				if config.ShowPublicIP {
+					ipBefore, ipBeforeErr = lookupPublicIP()
+				}
+				var result ConnectResult
+				var output string
+				if interruptible(func() {
+					result, output = timedConnect(selectedVPN, func(vpnName string) (string, error) {
+						return connectInteractiveWith(vpnName, opts)
+					})
+				}) {
+					abortConnect(selectedVPN)
+				}
+				output += result.Summary()
+				err := result.Err
+				if err == nil && config.ShowPublicIP {
+					output += publicIPChange(ipBefore, ipBeforeErr)
 				}
+				if err == nil && config.CheckDNSLeak && usingNmcli() {
+					output += dnsLeakWarning(selectedVPN)
+				}
+				ipv6Leaking := false
+				if err == nil && config.CheckIPv6Leak && usingNmcli() {
+					var warning string
+					warning, ipv6Leaking = ipv6LeakWarning(selectedVPN)
+					output += warning
+				}
+				if err == nil && config.PingAfterConnect {
+					output += gatewayLatency(selectedVPN) + "\n"
+				}
+				printResult(output, err)
 				
-				vpnForm := huh.NewForm(
-					huh.NewGroup(
-						huh.NewSelect[string]().
-							Title("Select VPN to connect").
-							Value(&selectedVPN).
-							Options(vpnOptions...),
+				if ipv6Leaking && confirm(fmt.Sprintf("Disable IPv6 on %s?", selectedVPN), "Sets ipv6.method to ignore; reconnect for it to take effect.") {
+					if err := disableIPv6(selectedVPN); err != nil {
+						printResult("", err)
+					} else {
+						fmt.Printf("Disabled IPv6 on %s, reconnect for the change to take effect\n", selectedVPN)
+					}
+				}
+				if err == nil && killSwitchAvailable() {
+					var enable bool
+					killSwitchForm := newForm(
+						newGroup(
+							huh.NewConfirm().
+								Title("Enable kill switch? Traffic is blocked if the VPN drops").
+								Value(&enable),
+						),
+					)
+					if err := killSwitchForm.Run(); err == nil && enable {
+						printResult(connectKillSwitch(selectedVPN))
+					}
+				}
+			}
+			
+		case ConnectLast:
+			last := lastConnectedVPN(getVPNList())
+			if last == "" {
+				return runAction(Connect)
+			}
+			if err := requireConnection(last); err != nil {
+				return err
+			}
+			if confirmCaptivePortal() {
+				printResult(connectInteractive(last))
+			}
+			
+		case DisconnectAll:
+			activeVpns := getActiveVPNs()
+			if len(activeVpns) == 0 {
+				fmt.Println("No active VPN connections")
+				return nil
+			}
+			if confirmDisconnect(activeVpns) && safetyDelay("Disconnecting all VPNs") {
+				printResult(disconnectVPNs(activeVpns))
+			}
+			
+		case RestartVPN:
+			activeVpns := getActiveVPNs()
+			if len(activeVpns) == 0 {
+				last := lastConnectedVPN(getVPNList())
+				if last == "" {
+					fmt.Println("No active VPN connections")
+					return nil
+				}
+				if confirm(fmt.Sprintf("No VPN is connected. Connect %s?", vpnDisplayName(last)), "") {
+					printResult(connectInteractive(last))
+				}
+				return nil
+			}
+			
+			for _, vpn := range activeVpns {
+				if err := reconnect(vpn); err != nil {
+					printResult("", err)
+				}
+			}
+			
+		case Reset:
+			if err := panicDisconnect(); err != nil {
+				printResult("", errors.New("emergency reset did not complete, see the failures above"))
+			}
+			
+		case Disconnect:
+			activeVpns := getActiveVPNs()
+			if len(activeVpns) == 0 {
+				fmt.Println("No active VPN connections")
+				return nil
+			}
+			if len(activeVpns) == 1 {
+				if confirmDisconnect(activeVpns) && safetyDelay("Disconnecting all VPNs") {
+					printResult(disconnectVPNs(activeVpns))
+				}
+				return nil
+			}
+			
+			vpnOptions := []huh.Option[string]{huh.NewOption("All active VPNs", disconnectAllOption)}
+			for _, vpn := range activeVpns {
+				vpnOptions = append(vpnOptions, vpnOption(vpn))
+			}
+			
+			var selectedVPNs []string
+			vpnForm := newForm(
+				newGroup(
+					huh.NewMultiSelect[string]().
+						Title("Select VPNs to disconnect").
+						Value(&selectedVPNs).
+						Options(vpnOptions...),
+				),
+			)
+			if err := vpnForm.Run(); err != nil || len(selectedVPNs) == 0 {
+				return nil
+			}
+			
+			if slices.Contains(selectedVPNs, disconnectAllOption) {
+				selectedVPNs = activeVpns
+			}
+			if confirmDisconnect(selectedVPNs) && safetyDelay("Disconnecting "+strings.Join(selectedVPNs, ", ")) {
+				printResult(disconnectVPNs(selectedVPNs))
+			}
+			
+		case ListVPNs:
+			output, err := listVPNs()
+			if err != nil {
+				printResult("", err)
+				return nil
+			}
+			showPaged(output)
+			
+		case Status:
+			tag, ok := selectTag()
+			if !ok {
+				return nil
+			}
+			fmt.Println("VPN Status:")
+			printResult(vpnStatus(tag))
+			
+		case QuickStatus:
+			fmt.Println(quickStatus())
+			
+		case AddVPN:
+			sources := []huh.Option[string]{
+				huh.NewOption("Config file (.ovpn or .conf)", "file"),
+				huh.NewOption("Config file, type the path", "path"),
+				huh.NewOption("Directory of config files", "dir"),
+				huh.NewOption("WireGuard link (wg:// or base64)", "wglink"),
+				huh.NewOption("Download from a URL (https)", "url"),
+			}
+			if usingNmcli() {
+				sources = append(sources, huh.NewOption("IKEv2 server (strongSwan)", "ikev2"))
+			}
+			
+			var source string
+			sourceForm := newForm(
+				newGroup(
+					huh.NewSelect[string]().
+						Title("Import from").
+						Value(&source).
+						Options(sources...),
+				),
+			)
+			if err := sourceForm.Run(); err != nil {
+				return nil
+			}
+			
+			if source == "wglink" {
+				var link string
+				name := "wg0"
+				linkForm := newForm(
+					newGroup(
+						huh.NewText().
+							Title("Paste WireGuard link").
+							Value(&link),
+						huh.NewInput().
+							Title("Connection name").
+							Value(&name).
+							Validate(validateWireGuardName),
 					),
-				).WithTheme(huh.ThemeCatppuccin())
-				
-				if err := vpnForm.Run(); err == nil && selectedVPN != "" {
-					fmt.Println(connectVPN(selectedVPN))
+				)
+				if err := linkForm.Run(); err == nil {
+					reportImport(importWireGuardLink(link, strings.TrimSpace(name)))
+				}
+				return nil
+			}
+			
+			if source == "ikev2" {
+				if warning := pluginWarning("strongswan"); warning != "" {
+					fmt.Println(warningStyle.Render(warning))
+				}
+				var name, server, user, certificate string
+				ikev2Form := newForm(
+					newGroup(
+						huh.NewInput().
+							Title("Connection name").
+							Value(&name).
+							Validate(func(name string) error {
+								return validateNewVPNName(name, getVPNList())
+							}),
+						huh.NewInput().
+							Title("Server address").
+							Placeholder("vpn.example.com").
+							Value(&server),
+						huh.NewInput().
+							Title("Username").
+							Value(&user),
+						huh.NewInput().
+							Title("CA certificate (leave empty to use the system CAs)").
+							Value(&certificate),
+					),
+				)
+				if err := ikev2Form.Run(); err == nil {
+					reportImport(addIKEv2(name, server, user, certificate))
+				}
+				return nil
+			}
+			
+			if source == "url" {
+				var link string
+				urlForm := newForm(
+					newGroup(
+						huh.NewInput().
+							Title("Enter profile download URL").
+							Placeholder("https://").
+							Value(&link),
+					),
+				)
+				if err := urlForm.Run(); err != nil || strings.TrimSpace(link) == "" {
+					return nil
 				}
 				
-			case Disconnect:
-				fmt.Println(disconnectVPN())
-				
-			case ListVPNs:
-				fmt.Println(listVPNs())
-				
-			case Status:
-				fmt.Println("VPN Status:")
-				fmt.Println(vpnStatus())
-				
-			case AddVPN:
-				var vpnFile string
-				vpnFileForm := huh.NewForm(
-					huh.NewGroup(
+				var output string
+				var err error
+				withSpinner("Downloading profile...", func() {
+					output, err = importFromURL(link)
+				})
+				reportImport(output, err)
+				return nil
+			}
+			
+			if source == "dir" {
+				var dir string
+				dirForm := newForm(
+					newGroup(
 						huh.NewInput().
-							Title("Enter path to .ovpn file").
-							Value(&vpnFile),
+							Title("Enter path to directory").
+							Value(&dir),
 					),
-				).WithTheme(huh.ThemeCatppuccin())
-				if err := vpnFileForm.Run(); err == nil {
-					fmt.Println(addVPN(strings.TrimSpace(vpnFile)))
+				)
+				if err := dirForm.Run(); err != nil {
+					return nil
 				}
 				
-			case RemoveVPN:
-				vpns := getVPNList()
-				if len(vpns) == 0 {
-					fmt.Println("No VPN connections available to remove")
+				results, err := importDirectory(strings.TrimSpace(dir))
+				if err != nil {
+					printResult("", err)
+					return nil
+				}
+				fmt.Print(formatImportResults(results))
+				return nil
+			}
+			
+			var vpnFile string
+			var fileField huh.Field = huh.NewInput().
+				Title("Enter path to .ovpn or .conf file").
+				Value(&vpnFile)
+			if source == "file" && !plain {
+				fileField = huh.NewFilePicker().
+					Title("Select a .ovpn or .conf file").
+					CurrentDirectory(importStartDir()).
+					AllowedTypes([]string{".ovpn", ".conf"}).
+					Picking(true).
+					Height(15).
+					Value(&vpnFile)
+			}
+			vpnFileForm := newForm(
+				newGroup(fileField),
+			)
+			if err := vpnFileForm.Run(); err != nil {
+				return nil
+			}
+			
+			vpnFile, err := resolveImportPath(vpnFile)
+			if err != nil {
+				printResult("", err)
+				return nil
+			}
+			vpnType, err := detectVPNType(vpnFile)
+			if err != nil {
+				fmt.Println("Error:", err)
+				return nil
+			}
+			if warning := pluginWarning(vpnType); warning != "" {
+				fmt.Println(warningStyle.Render(warning))
+			}
+			title := fmt.Sprintf("Import %s as %s?", filepath.Base(vpnFile), vpnType)
+			if vpnType == "openvpn" {
+				if findings := lintOVPN(vpnFile); len(findings) > 0 {
+					fmt.Printf("Problems found in %s:\n%s", filepath.Base(vpnFile), formatFindings(findings))
+					title = fmt.Sprintf("Import %s as %s anyway?", filepath.Base(vpnFile), vpnType)
+				}
+			}
+			
+			var confirmed bool
+			confirmForm := newForm(
+				newGroup(
+					huh.NewConfirm().
+						Title(title).
+						Value(&confirmed),
+				),
+			)
+			if err := confirmForm.Run(); err == nil && confirmed {
+				var output string
+				var err error
+				withSpinner("Importing "+filepath.Base(vpnFile)+"...", func() {
+					output, err = addVPN(vpnFile)
+				})
+				reportImport(output, err)
+			}
+			
+		case AvailableVPNs:
+			dir, err := configDir()
+			if err != nil {
+				return err
+			}
+			files, err := scanAvailableConfigs(dir)
+			if err != nil {
+				return err
+			}
+			if len(files) == 0 {
+				fmt.Printf("No .ovpn or .conf files found in %s (set config_dir to scan another directory)\n", dir)
+				return nil
+			}
+			
+			vpns := getVPNList()
+			var available []huh.Option[string]
+			fmt.Printf("Profiles in %s:\n", dir)
+			for _, file := range files {
+				if configImported(file, vpns) {
+					fmt.Printf("  %s (imported)\n", filepath.Base(file))
 					continue
 				}
+				fmt.Printf("  %s\n", filepath.Base(file))
+				available = append(available, huh.NewOption(filepath.Base(file), file))
+			}
+			if len(available) == 0 {
+				fmt.Println("All profiles are already imported")
+				return nil
+			}
+			
+			var selected []string
+			importForm := newForm(
+				newGroup(
+					huh.NewMultiSelect[string]().
+						Title("Select profiles to import").
+						Value(&selected).
+						Options(available...).
+						Filterable(len(available) > filterThreshold),
+				),
+			)
+			if err := importForm.Run(); err != nil || len(selected) == 0 {
+				return nil
+			}
+			fmt.Print(formatImportResults(importFiles(selected)))
+			
+		case EditVPN:
+			vpns := getVPNList()
+			if len(vpns) == 0 {
+				fmt.Println("No VPN connections available to edit")
+				return nil
+			}
+			
+			var selectedVPN string
+			vpnForm := newForm(
+				newGroup(
+					vpnSelect("Select VPN to edit", &selectedVPN, vpns),
+				),
+			)
+			
+			if err := vpnForm.Run(); err == nil && selectedVPN != "" {
+				printResult(editVPN(selectedVPN))
+			}
+			
+		case SetDNS:
+			vpns := getVPNList()
+			if len(vpns) == 0 {
+				fmt.Println("No VPN connections available")
+				return nil
+			}
+			
+			var selectedVPN string
+			vpnForm := newForm(
+				newGroup(
+					vpnSelect("Select VPN to set DNS servers for", &selectedVPN, vpns),
+				),
+			)
+			if err := vpnForm.Run(); err != nil || selectedVPN == "" {
+				return nil
+			}
+			
+			current, err := currentDNS(selectedVPN)
+			if err != nil {
+				return err
+			}
+			fmt.Println(current)
+			
+			servers := strings.Join(append(append([]string(nil), current.IPv4...), current.IPv6...), ", ")
+			dnsForm := newForm(
+				newGroup(
+					huh.NewInput().
+						Title(fmt.Sprintf("DNS servers for %s (comma separated, empty to use the VPN's)", selectedVPN)).
+						Value(&servers).
+						Validate(validateDNSList),
+				),
+			)
+			if err := dnsForm.Run(); err != nil {
+				return nil
+			}
+			
+			if err := setDNS(selectedVPN, splitServers(servers)); err != nil {
+				return err
+			}
+			if updated, err := currentDNS(selectedVPN); err == nil {
+				fmt.Println(updated)
+			}
+			fmt.Println("Reconnect for the change to take effect")
+			
+		case SetMTU:
+			vpns := getVPNList()
+			if len(vpns) == 0 {
+				fmt.Println("No VPN connections available")
+				return nil
+			}
+			
+			var selectedVPN string
+			vpnForm := newForm(
+				newGroup(
+					vpnSelect("Select VPN to set the MTU for", &selectedVPN, vpns),
+				),
+			)
+			if err := vpnForm.Run(); err != nil || selectedVPN == "" {
+				return nil
+			}
+			
+			current, err := currentMTU(selectedVPN)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("MTU: %s\n", formatMTU(current))
+			
+			value := formatMTU(current)
+			mtuForm := newForm(
+				newGroup(
+					huh.NewInput().
+						Title(fmt.Sprintf("MTU for %s (%d-%d, or auto)", selectedVPN, minMTU, maxMTU)).
+						Value(&value).
+						Validate(func(value string) error {
+							_, err := parseMTU(value)
+							return err
+						}),
+				),
+			)
+			if err := mtuForm.Run(); err != nil {
+				return nil
+			}
+			
+			mtu, _ := parseMTU(value)
+			if err := setMTU(selectedVPN, mtu); err != nil {
+				return err
+			}
+			fmt.Printf("MTU of %s set to %s, reconnect for the change to take effect\n", selectedVPN, formatMTU(mtu))
+			
+		case SplitTunnel:
+			vpns := getVPNList()
+			if len(vpns) == 0 {
+				fmt.Println("No VPN connections available")
+				return nil
+			}
+			
+			var selectedVPN string
+			vpnForm := newForm(
+				newGroup(
+					vpnSelect("Select VPN to configure split tunneling for", &selectedVPN, vpns),
+				),
+			)
+			if err := vpnForm.Run(); err != nil || selectedVPN == "" {
+				return nil
+			}
+			
+			routes, onlyRoutes, err := splitTunnel(selectedVPN)
+			if err != nil {
+				return err
+			}
+			if len(routes) == 0 {
+				fmt.Printf("%s has no extra routes\n", selectedVPN)
+			}
+			
+			keep := append([]string(nil), routes...)
+			var added string
+			var fields []huh.Field
+			if len(routes) > 0 {
+				fields = append(fields, huh.NewMultiSelect[string]().
+					Title("Routes to keep (deselect to remove)").
+					Options(huh.NewOptions(routes...)...).
+					Value(&keep))
+			}
+			fields = append(fields,
+				huh.NewInput().
+					Title("Subnets to add (comma separated CIDRs)").
+					Placeholder("10.0.0.0/8, 192.168.50.0/24").
+					Value(&added).
+					Validate(validateCIDRList),
+				huh.NewConfirm().
+					Title("Send only these subnets through the VPN?").
+					Description("Otherwise all traffic still goes through the VPN.").
+					Value(&onlyRoutes),
+			)
+			if err := newForm(newGroup(fields...)).Run(); err != nil {
+				return nil
+			}
+			
+			if err := setSplitTunnel(selectedVPN, keep, splitServers(added), onlyRoutes); err != nil {
+				return err
+			}
+			fmt.Printf("Updated routes for %s, reconnect for the change to take effect\n", selectedVPN)
+			
+		case RenameVPN:
+			vpns := getVPNList()
+			if len(vpns) == 0 {
+				fmt.Println("No VPN connections available to rename")
+				return nil
+			}
+			
+			var selectedVPN string
+			vpnForm := newForm(
+				newGroup(
+					vpnSelect("Select VPN to rename", &selectedVPN, vpns),
+				),
+			)
+			
+			if err := vpnForm.Run(); err == nil && selectedVPN != "" {
+				var newName string
+				nameForm := newForm(
+					newGroup(
+						huh.NewInput().
+							Title(fmt.Sprintf("New name for %s", selectedVPN)).
+							Value(&newName).
+							Validate(func(s string) error {
+								return validateNewVPNName(s, vpns)
+							}),
+					),
+				)
 				
-				var selectedVPN string
-				vpnOptions := make([]huh.Option[string], len(vpns))
-				for i, vpn := range vpns {
-					vpnOptions[i] = huh.NewOption[string](vpn, vpn)
+				if err := nameForm.Run(); err == nil {
+					printResult(renameVPN(selectedVPN, newName))
 				}
-				
-				vpnForm := huh.NewForm(
-					huh.NewGroup(
-						huh.NewSelect[string]().
-							Title("Select VPN to remove").
-							Value(&selectedVPN).
-							Options(vpnOptions...),
+			}
+			
+		case CloneVPN:
+			vpns := getVPNList()
+			if len(vpns) == 0 {
+				fmt.Println("No VPN connections available to clone")
+				return nil
+			}
+			
+			var selectedVPN string
+			vpnForm := newForm(
+				newGroup(
+					vpnSelect("Select VPN to clone", &selectedVPN, vpns),
+				),
+			)
+			
+			if err := vpnForm.Run(); err == nil && selectedVPN != "" {
+				var newName string
+				editAfter := true
+				cloneForm := newForm(
+					newGroup(
+						huh.NewInput().
+							Title(fmt.Sprintf("Name for the copy of %s", selectedVPN)).
+							Value(&newName).
+							Validate(func(s string) error {
+								return validateNewVPNName(s, vpns)
+							}),
+						huh.NewConfirm().
+							Title("Edit the copy now?").
+							Value(&editAfter),
 					),
-				).WithTheme(huh.ThemeCatppuccin())
+				)
+				if err := cloneForm.Run(); err != nil {
+					return nil
+				}
 				
-				if err := vpnForm.Run(); err == nil && selectedVPN != "" {
-					var confirmed bool
-					confirmForm := huh.NewForm(
-						huh.NewGroup(
-							huh.NewConfirm().
-								Title(fmt.Sprintf("Are you sure you want to remove %s?", selectedVPN)).
-								Value(&confirmed),
-						),
-					).WithTheme(huh.ThemeCatppuccin())
-					
-					if err := confirmForm.Run(); err == nil && confirmed {
-						fmt.Println(removeVPN(selectedVPN))
+				newName = strings.TrimSpace(newName)
+				if err := cloneVPN(selectedVPN, newName); err != nil {
+					printResult("", err)
+					return nil
+				}
+				fmt.Printf("Cloned %s as %s\n", selectedVPN, newName)
+				if editAfter {
+					printResult(editVPN(newName))
+				}
+			}
+			
+		case RemoveVPN:
+			vpns := getVPNList()
+			if len(vpns) == 0 {
+				fmt.Println("No VPN connections available to remove")
+				return nil
+			}
+			
+			vpnOptions := make([]huh.Option[string], len(vpns))
+			for i, vpn := range vpns {
+				vpnOptions[i] = vpnOption(vpn)
+			}
+			
+			var selectedVPNs []string
+			vpnForm := newForm(
+				newGroup(
+					huh.NewMultiSelect[string]().
+						Title("Select VPNs to remove").
+						Value(&selectedVPNs).
+						Options(vpnOptions...).
+						Filterable(len(vpns) > filterThreshold),
+				),
+			)
+			
+			if err := vpnForm.Run(); err == nil && len(selectedVPNs) > 0 {
+				var remaining []string
+				for _, vpn := range selectedVPNs {
+					if err := requireConnection(vpn); err != nil {
+						printResult("", err)
+						continue
 					}
+					remaining = append(remaining, vpn)
+				}
+				if selectedVPNs = remaining; len(selectedVPNs) == 0 {
+					return nil
 				}
 				
-			case ExportVPN:
-				vpns := getVPNList()
-				if len(vpns) == 0 {
-					fmt.Println("No VPN connections available to export")
-					continue
+				title := fmt.Sprintf("Are you sure you want to remove %s?", selectedVPNs[0])
+				if len(selectedVPNs) > 1 {
+					title = fmt.Sprintf("Are you sure you want to remove these %d VPNs?\n  %s", len(selectedVPNs), strings.Join(selectedVPNs, "\n  "))
 				}
 				
-				var selectedVPN string
-				vpnOptions := make([]huh.Option[string], len(vpns))
-				for i, vpn := range vpns {
-					vpnOptions[i] = huh.NewOption[string](vpn, vpn)
+				var confirmed bool
+				confirmForm := newForm(
+					newGroup(
+						huh.NewConfirm().
+							Title(title).
+							Value(&confirmed),
+					),
+				)
+				
+				if err := confirmForm.Run(); err == nil && confirmed && safetyDelay("Removing "+strings.Join(selectedVPNs, ", ")) {
+					printResult(removeVPNs(selectedVPNs, confirm))
+				}
+			}
+			
+		case UndoRemove:
+			path, err := lastBackup()
+			if err != nil {
+				printResult("", err)
+				return nil
+			}
+			name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+			if !confirm(fmt.Sprintf("Restore %s?", name), fmt.Sprintf("It was removed on %s.", backupTime(path))) {
+				return nil
+			}
+			if err := restoreFromBackup(path); err != nil {
+				printResult("", err)
+				return nil
+			}
+			fmt.Printf("Restored %s\n", name)
+			
+		case ExportVPN:
+			vpns := getVPNList()
+			if len(vpns) == 0 {
+				fmt.Println("No VPN connections available to export")
+				return nil
+			}
+			
+			var selectedVPN string
+			vpnForm := newForm(
+				newGroup(
+					vpnSelect("Select VPN to export", &selectedVPN, vpns),
+				),
+			)
+			
+			if err := vpnForm.Run(); err == nil && selectedVPN != "" {
+				if err := requireConnection(selectedVPN); err != nil {
+					printResult("", err)
+					return nil
 				}
 				
-				vpnForm := huh.NewForm(
-					huh.NewGroup(
+				destination := "file"
+				destinationForm := newForm(
+					newGroup(
 						huh.NewSelect[string]().
-							Title("Select VPN to export").
-							Value(&selectedVPN).
-							Options(vpnOptions...),
+							Title("Export to").
+							Value(&destination).
+							Options(
+								huh.NewOption("File", "file"),
+								huh.NewOption("Clipboard", "clipboard"),
+							),
 					),
-				).WithTheme(huh.ThemeCatppuccin())
+				)
+				if err := destinationForm.Run(); err != nil {
+					return nil
+				}
+				if destination == "clipboard" {
+					printResult(copyVPNConfig(selectedVPN, confirm))
+					return nil
+				}
 				
-				if err := vpnForm.Run(); err == nil && selectedVPN != "" {
-					var outputPath string
-					pathForm := huh.NewForm(
-						huh.NewGroup(
-							huh.NewInput().
-								Title("Enter export path (leave empty for default)").
-								Value(&outputPath).
-								Placeholder(fmt.Sprintf("~/Desktop/%s.ovpn", selectedVPN)),
-						),
-					).WithTheme(huh.ThemeCatppuccin())
-					
-					if err := pathForm.Run(); err == nil {
-						fmt.Println(exportVPN(selectedVPN, strings.TrimSpace(outputPath)))
+				placeholder := selectedVPN
+				if vpnType, err := connectionVPNType(selectedVPN); err == nil {
+					placeholder = defaultExportName(selectedVPN, vpnType)
+				}
+				if dir, err := exportDir(); err == nil {
+					placeholder = filepath.Join(dir, placeholder)
+				}
+				
+				var outputPath string
+				pathForm := newForm(
+					newGroup(
+						huh.NewInput().
+							Title("Enter export path (leave empty for default)").
+							Value(&outputPath).
+							Placeholder(placeholder),
+					),
+				)
+				
+				if err := pathForm.Run(); err == nil {
+					printResult(exportVPN(selectedVPN, strings.TrimSpace(outputPath), confirm))
+				}
+			}
+			
+		case ExportAll:
+			if len(getVPNs()) == 0 {
+				fmt.Println("No VPN connections available to export")
+				return nil
+			}
+			
+			placeholder, _ := exportDir()
+			var dir string
+			dirForm := newForm(
+				newGroup(
+					huh.NewInput().
+						Title("Export all VPNs to directory (leave empty for default)").
+						Value(&dir).
+						Placeholder(placeholder),
+				),
+			)
+			if err := dirForm.Run(); err != nil {
+				return nil
+			}
+			if dir = strings.TrimSpace(dir); dir == "" {
+				dir = placeholder
+			}
+			
+			var baseDir string
+			if config.RestrictExports {
+				baseDir = placeholder
+			}
+			dir, err := safeExportPath(dir, baseDir)
+			if err != nil {
+				return err
+			}
+			if err := ensureExportDir(dir, confirm); err != nil {
+				return err
+			}
+			if !confirm("Exported configs can contain private keys or credentials. Export all?",
+				fmt.Sprintf("Anyone who can read %s can use these connections.", dir)) {
+				return nil
+			}
+			fmt.Print(formatExportResults(dir, exportAll(dir)))
+			
+		case QRCode:
+			vpns := wireGuardVPNs()
+			if len(vpns) == 0 {
+				fmt.Println("No WireGuard connections available")
+				return nil
+			}
+			
+			var selectedVPN string
+			var includeKey bool
+			qrForm := newForm(
+				newGroup(
+					vpnSelect("Select WireGuard connection", &selectedVPN, vpns),
+					huh.NewConfirm().
+						Title("Include the private key?").
+						Description("Anyone who sees or photographs the code can then use this connection.").
+						Value(&includeKey),
+				),
+			)
+			if err := qrForm.Run(); err != nil || selectedVPN == "" {
+				return nil
+			}
+			
+			wgConfig, err := wireGuardConfig(selectedVPN)
+			if err != nil {
+				printResult("", err)
+				return nil
+			}
+			if !includeKey {
+				wgConfig = withoutPrivateKey(wgConfig)
+				fmt.Println("The private key is left out; add it in the phone app after scanning.")
+			}
+			printResult("", printQRCode(wgConfig))
+			
+		case FavoriteVPN:
+			var candidates []string
+			for _, vpn := range getVPNList() {
+				if !isFavorite(vpn) {
+					candidates = append(candidates, vpn)
+				}
+			}
+			if len(candidates) == 0 {
+				fmt.Println("No VPN connections available to add to favorites")
+				return nil
+			}
+			
+			var selectedVPN string
+			vpnForm := newForm(
+				newGroup(
+					vpnSelect("Select VPN to add to favorites", &selectedVPN, candidates),
+				),
+			)
+			
+			if err := vpnForm.Run(); err == nil && selectedVPN != "" {
+				if err := addFavorite(selectedVPN); err != nil {
+					printResult("", err)
+				} else {
+					fmt.Printf("Added %s to favorites\n", selectedVPN)
+				}
+			}
+			
+		case UnfavoriteVPN:
+			if len(config.Favorites) == 0 {
+				fmt.Println("No favorites to remove")
+				return nil
+			}
+			
+			var selectedVPN string
+			vpnForm := newForm(
+				newGroup(
+					vpnSelect("Select VPN to remove from favorites", &selectedVPN, config.Favorites),
+				),
+			)
+			
+			if err := vpnForm.Run(); err == nil && selectedVPN != "" {
+				if err := removeFavorite(selectedVPN); err != nil {
+					printResult("", err)
+				} else {
+					fmt.Printf("Removed %s from favorites\n", selectedVPN)
+				}
+			}
+			
+		case TagVPN:
+			vpns := getVPNList()
+			if len(vpns) == 0 {
+				fmt.Println("No VPN connections available")
+				return nil
+			}
+			
+			var selectedVPN string
+			vpnForm := newForm(
+				newGroup(
+					vpnSelect("Select VPN to tag", &selectedVPN, vpns),
+				),
+			)
+			if err := vpnForm.Run(); err != nil || selectedVPN == "" {
+				return nil
+			}
+			
+			tags := strings.Join(vpnTags(selectedVPN), ", ")
+			tagForm := newForm(
+				newGroup(
+					huh.NewInput().
+						Title(fmt.Sprintf("Tags for %s (comma separated, empty to remove all)", selectedVPN)).
+						Placeholder("clientA, testing").
+						Value(&tags).
+						Validate(validateTags),
+				),
+			)
+			if err := tagForm.Run(); err != nil {
+				return nil
+			}
+			if err := setTags(selectedVPN, parseTags(tags)); err != nil {
+				printResult("", err)
+			} else {
+				fmt.Printf("Updated tags of %s\n", selectedVPN)
+			}
+			
+		case ForgetSecret:
+			vpns := getVPNList()
+			if len(vpns) == 0 {
+				fmt.Println("No VPN connections available")
+				return nil
+			}
+			
+			var selectedVPN string
+			vpnForm := newForm(
+				newGroup(
+					vpnSelect("Select VPN to forget the saved password for", &selectedVPN, vpns),
+				),
+			)
+			
+			if err := vpnForm.Run(); err == nil && selectedVPN != "" {
+				if err := clearSecret(selectedVPN); err != nil {
+					printResult("", err)
+				} else {
+					fmt.Printf("Removed the saved password for %s from the keyring\n", selectedVPN)
+				}
+			}
+			
+		case Autoconnect:
+			infos := getAutoconnectInfo()
+			if len(infos) == 0 {
+				fmt.Println("No VPN connections available")
+				return nil
+			}
+			
+			current := map[string]bool{}
+			vpnOptions := make([]huh.Option[string], len(infos))
+			for i, info := range infos {
+				state := "off"
+				if info.Autoconnect {
+					state = "on"
+				}
+				current[info.Name] = info.Autoconnect
+				vpnOptions[i] = huh.NewOption[string](fmt.Sprintf("%s (autoconnect %s)", vpnLabel(info.Name), state), info.Name)
+			}
+			
+			var selectedVPN string
+			vpnForm := newForm(
+				newGroup(
+					huh.NewSelect[string]().
+						Title("Select VPN to toggle autoconnect").
+						Value(&selectedVPN).
+						Options(vpnOptions...).
+						Filtering(len(vpnOptions) > filterThreshold),
+				),
+			)
+			
+			if err := vpnForm.Run(); err == nil && selectedVPN != "" {
+				enabled := current[selectedVPN]
+				toggleForm := newForm(
+					newGroup(
+						huh.NewConfirm().
+							Title(fmt.Sprintf("Connect %s automatically?", selectedVPN)).
+							Value(&enabled),
+					),
+				)
+				
+				if err := toggleForm.Run(); err == nil {
+					if err := setAutoconnect(selectedVPN, enabled); err != nil {
+						printResult("", err)
+					} else if enabled {
+						fmt.Printf("Autoconnect enabled for %s\n", selectedVPN)
+					} else {
+						fmt.Printf("Autoconnect disabled for %s\n", selectedVPN)
 					}
 				}
+			}
+			
+		case Priority:
+			infos := getAutoconnectInfo()
+			if len(infos) == 0 {
+				fmt.Println("No VPN connections available")
+				return nil
+			}
+			fmt.Println(autoconnectOrder())
+			
+			var selectedVPN string
+			vpnOptions := make([]huh.Option[string], len(infos))
+			for i, info := range infos {
+				vpnOptions[i] = huh.NewOption[string](fmt.Sprintf("%s (%d)", info.Name, info.Priority), info.Name)
+			}
+			
+			vpnForm := newForm(
+				newGroup(
+					huh.NewSelect[string]().
+						Title("Select VPN to change priority").
+						Value(&selectedVPN).
+						Options(vpnOptions...).
+						Filtering(len(vpnOptions) > filterThreshold),
+				),
+			)
+			
+			if err := vpnForm.Run(); err == nil && selectedVPN != "" {
+				var priority string
+				priorityForm := newForm(
+					newGroup(
+						huh.NewInput().
+							Title(fmt.Sprintf("Autoconnect priority for %s (-999 to 999, higher wins)", selectedVPN)).
+							Value(&priority).
+							Validate(validatePriority),
+					),
+				)
 				
-			case Exit:
-				return
-		}
+				if err := priorityForm.Run(); err == nil {
+					prio, _ := strconv.Atoi(strings.TrimSpace(priority))
+					printResult(setAutoconnectPriority(selectedVPN, prio))
+					fmt.Println(autoconnectOrder())
+				}
+			}
+			
+		case Rotate:
+			vpns := getVPNList()
+			if len(vpns) < 2 {
+				fmt.Println("At least two VPN connections are needed to rotate")
+				return nil
+			}
+			
+			var selectedVPNs []string
+			interval := "1h"
+			vpnOptions := make([]huh.Option[string], len(vpns))
+			for i, vpn := range vpns {
+				vpnOptions[i] = vpnOption(vpn)
+			}
+			
+			rotateForm := newForm(
+				newGroup(
+					huh.NewMultiSelect[string]().
+						Title("Select VPNs to rotate between").
+						Value(&selectedVPNs).
+						Options(vpnOptions...).
+						Validate(func(selected []string) error {
+							if len(selected) < 2 {
+								return fmt.Errorf("select at least two VPNs")
+							}
+							return nil
+						}),
+					huh.NewInput().
+						Title("Switch interval").
+						Value(&interval).
+						Validate(validateInterval),
+				),
+			)
+			
+			if err := rotateForm.Run(); err == nil {
+				every, _ := time.ParseDuration(strings.TrimSpace(interval))
+				fmt.Println("Rotating VPNs, press Ctrl-C to stop")
+				ctx, stop := notifyInterrupt(os.Interrupt)
+				rotateVPNs(ctx, selectedVPNs, every)
+				stop()
+			}
+			
+		case Reconnect:
+			vpns := getVPNList()
+			if len(vpns) == 0 {
+				fmt.Println("No VPN connections available")
+				return nil
+			}
+			
+			var selectedVPN string
+			vpnForm := newForm(
+				newGroup(
+					vpnSelect("Select VPN to keep connected", &selectedVPN, vpns),
+				),
+			)
+			
+			if err := vpnForm.Run(); err == nil && selectedVPN != "" {
+				fmt.Printf("Keeping %s connected, press Ctrl-C to stop\n", selectedVPN)
+				ctx, stop := notifyInterrupt(os.Interrupt)
+				monitorAndReconnect(ctx, selectedVPN)
+				stop()
+			}
+			
+		case Traffic:
+			activeVpns := getActiveVPNs()
+			if len(activeVpns) == 0 {
+				fmt.Println("No active VPN connections")
+				return nil
+			}
+			
+			selectedVPN := activeVpns[0]
+			if len(activeVpns) > 1 {
+				vpnForm := newForm(
+					newGroup(
+						vpnSelect("Select VPN to watch", &selectedVPN, activeVpns),
+					),
+				)
+				if err := vpnForm.Run(); err != nil {
+					return nil
+				}
+			}
+			
+			if err := runTrafficView(selectedVPN); err != nil {
+				printResult("", err)
+			}
+			
+		case Usage:
+			fmt.Println(usageReport())
+			
+			var reset string
+			resetForm := newForm(
+				newGroup(
+					huh.NewSelect[string]().
+						Title("Reset usage counters?").
+						Value(&reset).
+						Options(usageResetOptions()...),
+				),
+			)
+			
+			if err := resetForm.Run(); err == nil && reset != "" {
+				if reset == "*" {
+					resetUsage("")
+				} else {
+					resetUsage(reset)
+				}
+				fmt.Println("Usage counters reset")
+			}
+			
+		case Benchmark:
+			vpns := getVPNList()
+			if len(vpns) == 0 {
+				fmt.Println("No VPN connections available")
+				return nil
+			}
+			
+			var selectedVPNs []string
+			vpnOptions := make([]huh.Option[string], len(vpns))
+			for i, vpn := range vpns {
+				vpnOptions[i] = vpnOption(vpn)
+			}
+			
+			benchmarkForm := newForm(
+				newGroup(
+					huh.NewMultiSelect[string]().
+						Title("Select VPNs to compare").
+						Description("Each one is connected, tested and disconnected in turn").
+						Value(&selectedVPNs).
+						Options(vpnOptions...),
+				),
+			)
+			
+			if err := benchmarkForm.Run(); err != nil || len(selectedVPNs) == 0 {
+				return nil
+			}
+			if active := getActiveVPNs(); len(active) > 0 {
+				fmt.Println("Disconnecting active VPNs so they don't skew the results")
+				printResult(disconnectVPN())
+			}
+			fmt.Println(formatBenchmark(benchmarkVPNs(selectedVPNs)))
+			
+		case TestVPN:
+			vpns := getVPNList()
+			if len(vpns) == 0 {
+				fmt.Println("No VPN connections available")
+				return nil
+			}
+			
+			var selectedVPNs []string
+			vpnOptions := make([]huh.Option[string], len(vpns))
+			for i, vpn := range vpns {
+				vpnOptions[i] = vpnOption(vpn)
+			}
+			
+			testForm := newForm(
+				newGroup(
+					huh.NewMultiSelect[string]().
+						Title("Select VPNs to test").
+						Description("Each one is connected, checked and disconnected again").
+						Value(&selectedVPNs).
+						Options(vpnOptions...).
+						Filterable(len(vpns) > filterThreshold),
+				),
+			)
+			
+			if err := testForm.Run(); err != nil || len(selectedVPNs) == 0 {
+				return nil
+			}
+			active := getActiveVPNs()
+			protected := ""
+			for _, vpn := range active {
+				if killSwitchProtects(vpn) {
+					protected = vpn
+				}
+			}
+			if len(active) > 0 {
+				if !confirm(fmt.Sprintf("Disconnect %s so it doesn't affect the test?", strings.Join(active, ", ")),
+					"It is connected again once the tests are done.") {
+					return nil
+				}
+				printResult(disconnectVPNs(active))
+			}
+			
+			var reports []TestReport
+			for _, vpn := range selectedVPNs {
+				fmt.Printf("Testing %s...\n", vpn)
+				report, _ := testVPN(vpn)
+				reports = append(reports, report)
+			}
+			fmt.Print(formatTestReports(reports))
+			
+			for _, vpn := range active {
+				fmt.Printf("Connecting %s again...\n", vpnDisplayName(vpn))
+				printResult(connectInteractive(vpn))
+			}
+			if protected != "" && slices.Contains(getActiveVPNs(), protected) {
+				printResult(connectKillSwitch(protected))
+			}
+			
+		case AuditVPNs:
+			vpns := getVPNList()
+			if len(vpns) == 0 {
+				fmt.Println("No VPN connections available")
+				return nil
+			}
+			
+			findings := map[string][]Finding{}
+			withSpinner(fmt.Sprintf("Checking %d profiles...", len(vpns)), func() {
+				for _, vpn := range vpns {
+					findings[vpn] = auditVPN(vpn)
+				}
+			})
+			fmt.Print(formatAudit(vpns, findings))
+			
+		case DryRun:
+			setDryRun(!dryRun)
+			if dryRun {
+				fmt.Println("Dry run on: commands that would change something are printed instead of run")
+			} else {
+				fmt.Println("Dry run off")
+			}
+			
+		case Overrides:
+			vpns := getVPNList()
+			if len(vpns) == 0 {
+				fmt.Println("No VPN connections available")
+				return nil
+			}
+			
+			var selectedVPN string
+			vpnForm := newForm(
+				newGroup(
+					vpnSelect("Select VPN to inspect", &selectedVPN, vpns),
+				),
+			)
+			
+			if err := vpnForm.Run(); err == nil && selectedVPN != "" {
+				printResult(showOverrides(selectedVPN))
+			}
+			
+		case Dashboard:
+			return runDashboard()
+			
+		case Exit:
+			return errExit
 	}
+	return nil
 }
\ No newline at end of file
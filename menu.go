@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var menuHelpStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+// menuShortcuts are the single keys of the main menu. While the list is being
+// filtered with / keys go to the filter instead.
+var menuShortcuts = map[string]Action{
+	"c": ConnectLast,
+	"d": DisconnectAll,
+	"s": Status,
+	"q": Exit,
+}
+
+// menuModel wraps the main menu form so single keys can pick an action
+// without going through the list.
+type menuModel struct {
+	form   *huh.Form
+	action Action
+}
+
+func (m menuModel) Init() tea.Cmd {
+	return m.form.Init()
+}
+
+func (m menuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && !filtering(m.form) {
+		if action, ok := menuShortcuts[msg.String()]; ok {
+			m.action = action
+			return m, tea.Quit
+		}
+	}
+	
+	form, cmd := m.form.Update(escFilter(m.form, msg))
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State != huh.StateNormal {
+		return m, tea.Quit
+	}
+	return m, cmd
+}
+
+func (m menuModel) View() string {
+	help := "c connect last VPN • d disconnect all • s status • q quit"
+	return m.form.View() + "\n" + menuHelpStyle.Render(help) + "\n"
+}
+
+// runMainMenu asks for the next action, either from the list or by shortcut.
+// With --plain the list is asked for line by line without shortcuts.
+func runMainMenu() (Action, error) {
+	var action Action
+	form := newForm(
+		newGroup(
+			huh.NewSelect[Action]().
+				Title("Choose an action").
+				Description(menuStatus()).
+				Value(&action).
+				Options(menuOptions()...),
+		),
+	)
+	if plain {
+		err := form.Run()
+		return action, err
+	}
+	
+	final, err := tea.NewProgram(menuModel{form: form.Form}).Run()
+	if err != nil {
+		return "", fmt.Errorf("menu: %w", err)
+	}
+	m := final.(menuModel)
+	switch {
+		case m.action != "":
+			return m.action, nil
+		case m.form.State == huh.StateAborted:
+			return "", huh.ErrUserAborted
+	}
+	return action, nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+)
+
+func TestMenuShortcutsReturnAfterFiltering(t *testing.T) {
+	var action Action
+	var m tea.Model = menuModel{form: newForm(newGroup(huh.NewSelect[Action]().
+		Options(huh.NewOption("Connect", Connect), huh.NewOption("Status", Status)).
+		Value(&action))).Form}
+	m.Init()
+	press := func(msg tea.KeyMsg) {
+		m, _ = m.Update(msg)
+	}
+	
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if got := m.(menuModel).action; got != "" {
+		t.Fatalf("c while filtering picked %q", got)
+	}
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if filtering(m.(menuModel).form) {
+		t.Fatal("still filtering after ESC")
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if got := m.(menuModel).action; got != Status {
+		t.Errorf("s after leaving the filter picked %q, want %q", got, Status)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	minMTU = 1280
+	maxMTU = 1500
+)
+
+// currentMTU returns the MTU configured on a connection, 0 meaning automatic.
+func currentMTU(vpnName string) (int, error) {
+	vpnType, err := connectionVPNType(vpnName)
+	if err != nil {
+		return 0, err
+	}
+	
+	var value string
+	switch vpnType {
+		case "wireguard":
+			output, err := executeCommand("nmcli", "-g", "wireguard.mtu", "connection", "show", vpnName)
+			if err != nil {
+				return 0, err
+			}
+			value = output
+		case "openvpn":
+			settings, err := readVPNSettings(vpnName)
+			if err != nil {
+				return 0, err
+			}
+			value = settings.Data["tunnel-mtu"]
+		default:
+			return 0, fmt.Errorf("setting the MTU of %s connections is not supported", vpnType)
+	}
+	if value = strings.TrimSpace(value); value == "" || value == "auto" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// setMTU sets the MTU of a WireGuard or OpenVPN connection. 0 goes back to
+// the automatic MTU.
+func setMTU(vpnName string, mtu int) error {
+	if mtu != 0 && (mtu < minMTU || mtu > maxMTU) {
+		return fmt.Errorf("MTU must be between %d and %d", minMTU, maxMTU)
+	}
+	vpnType, err := connectionVPNType(vpnName)
+	if err != nil {
+		return err
+	}
+	
+	args := []string{"connection", "modify", vpnName}
+	switch {
+		case vpnType == "wireguard":
+			args = append(args, "wireguard.mtu", strconv.Itoa(mtu))
+		case vpnType == "openvpn" && mtu == 0:
+			args = append(args, "-vpn.data", "tunnel-mtu")
+		case vpnType == "openvpn":
+			args = append(args, "+vpn.data", fmt.Sprintf("tunnel-mtu=%d", mtu))
+		default:
+			return fmt.Errorf("setting the MTU of %s connections is not supported", vpnType)
+	}
+	_, err = executeCommand("nmcli", args...)
+	return err
+}
+
+func parseMTU(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, "auto") {
+		return 0, nil
+	}
+	mtu, err := strconv.Atoi(value)
+	if err != nil || mtu < minMTU || mtu > maxMTU {
+		return 0, fmt.Errorf("enter a number between %d and %d, or auto", minMTU, maxMTU)
+	}
+	return mtu, nil
+}
+
+func formatMTU(mtu int) string {
+	if mtu == 0 {
+		return "auto"
+	}
+	return strconv.Itoa(mtu)
+}
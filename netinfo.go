@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const publicIPURL = "https://ipinfo.io/json"
+
+type IPInfo struct {
+	IP      string `json:"ip"`
+	City    string `json:"city"`
+	Region  string `json:"region"`
+	Country string `json:"country"`
+	Org     string `json:"org"`
+}
+
+func fetchPublicIP(ctx context.Context) (IPInfo, error) {
+	var info IPInfo
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, publicIPURL, nil)
+	if err != nil {
+		return info, err
+	}
+	req.Header.Set("Accept", "application/json")
+	
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return info, err
+	}
+	defer resp.Body.Close()
+	
+	if resp.StatusCode != http.StatusOK {
+		return info, fmt.Errorf("unexpected response from %s: %s", publicIPURL, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return info, err
+	}
+	return info, nil
+}
+
+func (info IPInfo) String() string {
+	var place []string
+	for _, part := range []string{info.City, info.Country} {
+		if part != "" {
+			place = append(place, part)
+		}
+	}
+	if len(place) == 0 {
+		return info.IP
+	}
+	return fmt.Sprintf("%s (%s)", info.IP, strings.Join(place, ", "))
+}
+
+func lookupPublicIP() (IPInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	return fetchPublicIP(ctx)
+}
+
+// publicIPChange reports the public IP after connecting next to the one seen
+// before, so it is obvious whether traffic now leaves through the VPN.
+func publicIPChange(before IPInfo, beforeErr error) string {
+	after, err := lookupPublicIP()
+	if err != nil {
+		return fmt.Sprintf("Public IP: could not look up (%s)\n", err)
+	}
+	if beforeErr != nil {
+		return fmt.Sprintf("Public IP: %s\n", after)
+	}
+	if before.IP == after.IP {
+		return warningStyle.Render(fmt.Sprintf("Public IP unchanged: %s", after)) + "\n"
+	}
+	return fmt.Sprintf("Public IP: %s -> %s\n", before, after)
+}
+
+func vpnInterface(vpnName string) string {
+	if !usingNmcli() {
+		return vpnName
+	}
+	output, err := executeCommand("nmcli", "-g", "GENERAL.IP-IFACE,GENERAL.DEVICES", "connection", "show", vpnName)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if iface := strings.TrimSpace(strings.Split(line, ",")[0]); iface != "" {
+			return iface
+		}
+	}
+	return ""
+}
+
+func readInterfaceStats(iface string) (rx, tx uint64, err error) {
+	read := func(counter string) (uint64, error) {
+		data, err := os.ReadFile(filepath.Join("/sys/class/net", iface, "statistics", counter))
+		if err != nil {
+			return 0, err
+		}
+		return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	}
+	
+	if rx, err = read("rx_bytes"); err != nil {
+		return 0, 0, err
+	}
+	if tx, err = read("tx_bytes"); err != nil {
+		return 0, 0, err
+	}
+	return rx, tx, nil
+}
+
+func measureThroughput(iface string, interval time.Duration) (rxRate, txRate float64, err error) {
+	rx1, tx1, err := readInterfaceStats(iface)
+	if err != nil {
+		return 0, 0, err
+	}
+	time.Sleep(interval)
+	rx2, tx2, err := readInterfaceStats(iface)
+	if err != nil {
+		return 0, 0, err
+	}
+	
+	seconds := interval.Seconds()
+	return float64(rx2-rx1) / seconds, float64(tx2-tx1) / seconds, nil
+}
+
+func formatBytes(bytes float64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	unit := 0
+	for bytes >= 1024 && unit < len(units)-1 {
+		bytes /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%.0f %s", bytes, units[unit])
+	}
+	return fmt.Sprintf("%.1f %s", bytes, units[unit])
+}
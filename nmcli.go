@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+type nmcliBackend struct{}
+
+func (nmcliBackend) Name() string {
+	return "nmcli"
+}
+
+func (nmcliBackend) List() ([]VPN, error) {
+	output, err := executeCommand("nmcli", "-t", "-f", "NAME,UUID,TYPE,ACTIVE", "connection", "show")
+	if err != nil {
+		return nil, err
+	}
+	return parseVPNList(output), nil
+}
+
+// parseVPNList picks the VPN and WireGuard connections out of
+// `nmcli -t -f NAME,UUID,TYPE,ACTIVE connection show`.
+func parseVPNList(output string) []VPN {
+	var vpns []VPN
+	for _, line := range outputLines(output) {
+		fields := parseTerseLine(line)
+		if len(fields) < 4 || (fields[2] != "vpn" && fields[2] != "wireguard") {
+			continue
+		}
+		vpns = append(vpns, VPN{Name: fields[0], UUID: fields[1], Type: fields[2], Active: fields[3] == "yes"})
+	}
+	return vpns
+}
+
+func (b nmcliBackend) Connect(name string) (string, error) {
+	return b.ConnectWithSecret(name, "", "", "")
+}
+
+func (nmcliBackend) ConnectWithSecret(name string, secret string, otp string, device string) (string, error) {
+	return activateVPN(name, secret, otp, device)
+}
+
+func (nmcliBackend) Disconnect(name string) (string, error) {
+	timeout := connectTimeout(name)
+	output, err := executeCommandTimeout(timeout, "nmcli", "connection", "down", name)
+	return output, timeoutError(err, "disconnect", timeout)
+}
+
+// statusWorkers bounds the nmcli calls Status runs at once.
+const statusWorkers = 4
+
+func (b nmcliBackend) Status() ([]VPNStatus, error) {
+	vpns, err := b.List()
+	if err != nil {
+		return nil, err
+	}
+	
+	var active []VPN
+	for _, vpn := range vpns {
+		if vpn.Active {
+			active = append(active, vpn)
+		}
+	}
+	
+	// Look the connections up in parallel, each into its own slot so the
+	// order stays that of the list.
+	results := make([]*VPNStatus, len(active))
+	errs := make([]error, len(active))
+	slots := make(chan struct{}, statusWorkers)
+	var wg sync.WaitGroup
+	for i, vpn := range active {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			
+			id := vpn.Name
+			if vpn.UUID != "" {
+				id = vpn.UUID
+			}
+			details, err := executeCommand("nmcli", "-t", "connection", "show", id)
+			if err != nil {
+				errs[i] = fmt.Errorf("could not read status of %s: %w", vpn.Name, err)
+				return
+			}
+			status := parseConnectionShow(details)
+			results[i] = &status
+		}()
+	}
+	wg.Wait()
+	
+	var statuses []VPNStatus
+	for _, status := range results {
+		if status != nil {
+			statuses = append(statuses, *status)
+		}
+	}
+	return statuses, errors.Join(errs...)
+}
+
+func (nmcliBackend) Import(path string) (string, error) {
+	vpnType, err := detectVPNType(path)
+	if err != nil {
+		return "", err
+	}
+	return importConnection(vpnType, path)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testConnectionShow = `Wired connection 1:6b1c9e0e-0001-4a4e-9a5c-000000000001:802-3-ethernet:yes
+Work:6b1c9e0e-0002-4a4e-9a5c-000000000002:vpn:yes
+Tokyo:6b1c9e0e-0003-4a4e-9a5c-000000000003:wireguard:no
+Home:6b1c9e0e-0004-4a4e-9a5c-000000000004:vpn:no
+`
+
+func TestListVPNs(t *testing.T) {
+	useFakeRunner(t, map[string]string{
+		"nmcli -t -f NAME,UUID,TYPE,ACTIVE connection show":                      testConnectionShow,
+		"nmcli -t -f NAME,TYPE,AUTOCONNECT,AUTOCONNECT-PRIORITY connection show": "Work:vpn:yes:0\nTokyo:wireguard:no:0\nHome:vpn:no:0\n",
+		"nmcli -t -f connection.id,vpn.service-type connection show Work Home":   "connection.id:Work\nvpn.service-type:org.freedesktop.NetworkManager.openvpn\nconnection.id:Home\nvpn.service-type:org.freedesktop.NetworkManager.openvpn\n",
+	})
+	
+	output, err := listVPNs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Available VPN connections (* = autoconnect):\n" +
+		"\nOpenVPN\n  1. Home (openvpn)\n  2. Work (openvpn) *\n" +
+		"\nWireGuard\n  3. Tokyo (wireguard)\n"
+	if output != want {
+		t.Errorf("listVPNs() =\n%s\nwant\n%s", output, want)
+	}
+}
+
+func TestListVPNsEmpty(t *testing.T) {
+	useFakeRunner(t, map[string]string{
+		"nmcli -t -f NAME,UUID,TYPE,ACTIVE connection show": "Wired connection 1:6b1c9e0e-0001-4a4e-9a5c-000000000001:802-3-ethernet:yes\n",
+	})
+	
+	output, err := listVPNs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != "No VPN connections found" {
+		t.Errorf("listVPNs() = %q", output)
+	}
+}
+
+func TestGetActiveVPNs(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{"none", "", nil},
+		{"ethernet only", "Wired connection 1:6b1c9e0e-0001-4a4e-9a5c-000000000001:802-3-ethernet:yes\n", nil},
+		{"one active", testConnectionShow, []string{"Work"}},
+		{
+			"duplicate names",
+			"Work:6b1c9e0e-0002-4a4e-9a5c-000000000002:vpn:yes\nWork:6b1c9e0e-0005-4a4e-9a5c-000000000005:vpn:yes\n",
+			[]string{"6b1c9e0e-0002-4a4e-9a5c-000000000002", "6b1c9e0e-0005-4a4e-9a5c-000000000005"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useFakeRunner(t, map[string]string{
+				"nmcli -t -f NAME,UUID,TYPE,ACTIVE connection show": test.output,
+			})
+			if got := getActiveVPNs(); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("getActiveVPNs() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDuplicateNamesShareConfig(t *testing.T) {
+	useFakeRunner(t, map[string]string{
+		"nmcli -t -f NAME,UUID,TYPE,ACTIVE connection show": "Work:6b1c9e0e-0002-4a4e-9a5c-000000000002:vpn:yes\nWork:6b1c9e0e-0005-4a4e-9a5c-000000000005:vpn:no\n",
+	})
+	config.Pass = map[string]string{"Work": "vpn/work"}
+	config.Favorites = []string{"Work"}
+	
+	id := getVPNList()[1]
+	if got := passEntry(id); got != "vpn/work" {
+		t.Errorf("passEntry(%s) = %q, want the entry of Work", id, got)
+	}
+	if !isFavorite(id) {
+		t.Errorf("%s isn't a favorite like Work", id)
+	}
+	if exists, err := connectionExists(id); err != nil || !exists {
+		t.Errorf("connectionExists(%s) = %v, %v", id, exists, err)
+	}
+}
+
+func TestExportVPN(t *testing.T) {
+	fake := useFakeRunner(t, map[string]string{
+		"nmcli -g connection.type,vpn.service-type connection show Work": "vpn\norg.freedesktop.NetworkManager.openvpn\n",
+		"nmcli connection export Work":                                   "client\nremote vpn.example.com 1194\n",
+	})
+	dir := t.TempDir()
+	
+	output, err := exportVPN("Work", filepath.Join(dir, "work"), func(string, string) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "work.ovpn")
+	if !strings.Contains(output, path) {
+		t.Errorf("exportVPN() = %q, want it to mention %s", output, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "client\nremote vpn.example.com 1194\n" {
+		t.Errorf("exported %q", data)
+	}
+	if len(fake.commands) != 2 {
+		t.Errorf("ran %d commands, want 2: %+v", len(fake.commands), fake.commands)
+	}
+}
+
+func TestExportVPNAsksBeforeWritingSecrets(t *testing.T) {
+	useFakeRunner(t, map[string]string{
+		"nmcli -g connection.type,vpn.service-type connection show Tokyo": "wireguard\n\n",
+		"nmcli connection export Tokyo":                                   "[Interface]\nPrivateKey = aGVsbG8=\n",
+	})
+	dir := t.TempDir()
+	
+	output, err := exportVPN("Tokyo", filepath.Join(dir, "tokyo"), func(string, string) bool { return false })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != "Export cancelled" {
+		t.Errorf("exportVPN() = %q", output)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tokyo.conf")); err == nil {
+		t.Error("config with a private key written without confirmation")
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"os/exec"
+)
+
+// notifyEvent sends a connection event notification unless notifications are
+// turned off in the config.
+func notifyEvent(body string) {
+	if config.Notifications {
+		notify("charmvpn", body)
+	}
+}
+
+func notify(title, body string) {
+	if _, err := exec.LookPath("notify-send"); err != nil {
+		return
+	}
+	executeCommand("notify-send", "--app-name=charmvpn", title, body)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+)
+
+// otpAttempts bounds how often a new one-time code is asked for after the
+// server rejects the login.
+const otpAttempts = 3
+
+const otpAppend = "append"
+
+var authFailedMessages = []string{
+	"auth_failed",
+	"authentication failed",
+	"login failed",
+}
+
+// otpMode returns how the one-time code of vpnName is passed: appended to
+// the password, or as the named vpn.secrets key. Empty means no OTP.
+func otpMode(vpnName string) string {
+	return strings.TrimSpace(config.OTP[configName(vpnName)])
+}
+
+func authFailed(output string) bool {
+	lower := strings.ToLower(output)
+	for _, msg := range authFailedMessages {
+		if strings.Contains(lower, msg) {
+			return true
+		}
+	}
+	return needsSecrets(output)
+}
+
+// otpSecrets builds the passwd-file handed to nmcli.
+func otpSecrets(vpnName string, secret string, otp string) string {
+	if otpMode(vpnName) == otpAppend {
+		return fmt.Sprintf("vpn.secrets.password:%s%s\n", secret, otp)
+	}
+	
+	var passwd strings.Builder
+	if secret != "" {
+		passwd.WriteString(fmt.Sprintf("vpn.secrets.password:%s\n", secret))
+	}
+	if otp != "" {
+		passwd.WriteString(fmt.Sprintf("vpn.secrets.%s:%s\n", otpMode(vpnName), otp))
+	}
+	return passwd.String()
+}
+
+func promptOTP(vpnName string) (string, bool) {
+	var otp string
+	form := newForm(
+		newGroup(
+			huh.NewInput().
+				Title(fmt.Sprintf("One-time code for %s", vpnName)).
+				EchoMode(huh.EchoModePassword).
+				Value(&otp),
+		),
+	)
+	if err := form.Run(); err != nil || strings.TrimSpace(otp) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(otp), true
+}
+
+// connectWithOTP connects a VPN that needs a one-time code. The code is always
+// prompted for and never stored; a rejected login asks for a fresh one.
+func connectWithOTP(sb secretBackend, vpnName string, device string, secret string, askSecret func() (string, bool)) (string, error) {
+	if askSecret == nil {
+		return "", fmt.Errorf("%s needs a one-time code: connect from a terminal", vpnName)
+	}
+	if secret == "" {
+		var ok bool
+		if secret, ok = askSecret(); !ok {
+			return "", fmt.Errorf("no password entered for %s", vpnName)
+		}
+	}
+	
+	for attempt := 1; ; attempt++ {
+		otp, ok := promptOTP(vpnName)
+		if !ok {
+			return "", fmt.Errorf("no one-time code entered for %s", vpnName)
+		}
+		output, err := sb.ConnectWithSecret(vpnName, secret, otp, device)
+		if err == nil || attempt == otpAttempts || !authFailed(output+err.Error()) {
+			return output, err
+		}
+		fmt.Println(errorStyle.Render("Authentication failed, try a new code"))
+	}
+}
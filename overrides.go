@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var genericDefaults = map[string]bool{
+	"":         true,
+	"--":       true,
+	"-1":       true,
+	"0":        true,
+	"no":       true,
+	"auto":     true,
+	"0x0":      true,
+	"none":     true,
+	"default":  true,
+	"unknown":  true,
+	"<hidden>": true,
+}
+
+var propertyDefaults = map[string]string{
+	"ipv4.may-fail":           "yes",
+	"ipv4.dhcp-send-hostname": "yes",
+	"ipv6.may-fail":           "yes",
+	"ipv6.dhcp-send-hostname": "yes",
+	"ipv6.addr-gen-mode":      "stable-privacy",
+}
+
+var overrideSettings = []string{"vpn.", "wireguard.", "ipv4.", "ipv6."}
+
+func isOverrideSetting(key string) bool {
+	for _, prefix := range overrideSettings {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isDefaultValue(key, value string) bool {
+	value = strings.TrimSpace(value)
+	if def, ok := propertyDefaults[key]; ok {
+		return value == def || value == "default"
+	}
+	if genericDefaults[value] {
+		return true
+	}
+	// nmcli annotates enum values, e.g. "-1 (default)" or "0x0 (none)".
+	if i := strings.Index(value, " ("); i >= 0 {
+		return genericDefaults[value[:i]] || genericDefaults[strings.Trim(value[i+1:], "()")]
+	}
+	return false
+}
+
+func connectionOverrides(vpnName string) (map[string]string, error) {
+	output, err := executeCommand("nmcli", "-t", "connection", "show", vpnName)
+	if err != nil {
+		return nil, err
+	}
+	
+	overrides := map[string]string{}
+	for _, line := range outputLines(output) {
+		key, value, ok := terseProperty(line)
+		if !ok || !isOverrideSetting(key) || isDefaultValue(key, value) {
+			continue
+		}
+		overrides[key] = value
+	}
+	return overrides, nil
+}
+
+func showOverrides(vpnName string) (string, error) {
+	overrides, err := connectionOverrides(vpnName)
+	if err != nil {
+		return "", err
+	}
+	if len(overrides) == 0 {
+		return fmt.Sprintf("%s uses NetworkManager defaults only", vpnName), nil
+	}
+	
+	keys := make([]string, 0, len(overrides))
+	for key := range overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("# %s: non-default settings\n", vpnName))
+	for _, key := range keys {
+		result.WriteString(fmt.Sprintf("%s = %s\n", key, overrides[key]))
+	}
+	return result.String(), nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ovpnFileDirectives name a file as their first argument, unless it is
+// "[inline]" or the contents follow in a <directive> block.
+var ovpnFileDirectives = map[string]bool{
+	"ca":             true,
+	"cert":           true,
+	"key":            true,
+	"tls-auth":       true,
+	"tls-crypt":      true,
+	"tls-crypt-v2":   true,
+	"pkcs12":         true,
+	"secret":         true,
+	"auth-user-pass": true,
+	"crl-verify":     true,
+	"dh":             true,
+}
+
+// ovpnAuthDirectives each give the client a way to authenticate.
+var ovpnAuthDirectives = []string{"cert", "pkcs12", "auth-user-pass", "secret"}
+
+var ovpnDeprecated = map[string]string{
+	"comp-lzo":                 "is deprecated, use compress or allow-compression",
+	"ns-cert-type":             "was removed in OpenVPN 2.5, use remote-cert-tls server",
+	"tls-remote":               "was removed in OpenVPN 2.4, use verify-x509-name",
+	"key-method":               "was removed in OpenVPN 2.5",
+	"client-cert-not-required": "was removed in OpenVPN 2.5, use verify-client-cert none",
+	"keysize":                  "was removed in OpenVPN 2.6",
+	"ncp-disable":              "was removed in OpenVPN 2.6, use data-ciphers",
+}
+
+// lintOVPN catches common breakage in an OpenVPN profile before it is
+// imported: no remote, no way to authenticate, deprecated directives and
+// referenced files that don't exist. It is not a full parser.
+func lintOVPN(path string) []Finding {
+	file, err := os.Open(path)
+	if err != nil {
+		return []Finding{{severityError, err.Error()}}
+	}
+	defer file.Close()
+	
+	var findings []Finding
+	seen := map[string]bool{}
+	block := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if block != "" {
+			if line == "</"+block+">" {
+				block = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "<") && strings.HasSuffix(line, ">") && !strings.HasPrefix(line, "</") {
+			block = strings.Trim(line, "<>")
+			seen[block] = true
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], ";") {
+			continue
+		}
+		
+		directive := fields[0]
+		seen[directive] = true
+		if hint, ok := ovpnDeprecated[directive]; ok {
+			findings = append(findings, Finding{severityWarning, directive + " " + hint})
+		}
+		if ovpnFileDirectives[directive] && len(fields) > 1 && fields[1] != "[inline]" {
+			ref := strings.Trim(fields[1], `"'`)
+			if !filepath.IsAbs(ref) {
+				ref = filepath.Join(filepath.Dir(path), ref)
+			}
+			if _, err := os.Stat(ref); err != nil {
+				findings = append(findings, Finding{severityError, fmt.Sprintf("%s file %s does not exist", directive, fields[1])})
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return append(findings, Finding{severityError, err.Error()})
+	}
+	
+	if !seen["remote"] && !seen["connection"] {
+		findings = append(findings, Finding{severityError, "no remote line, so there is no server to connect to"})
+	}
+	hasAuth := false
+	for _, directive := range ovpnAuthDirectives {
+		hasAuth = hasAuth || seen[directive]
+	}
+	if !hasAuth {
+		findings = append(findings, Finding{severityWarning, "no recognizable authentication (cert and key, pkcs12, auth-user-pass or secret)"})
+	} else if seen["cert"] && !seen["key"] {
+		findings = append(findings, Finding{severityWarning, "a client certificate is set but no key"})
+	}
+	return findings
+}
+
+func formatFindings(findings []Finding) string {
+	var result strings.Builder
+	for _, finding := range findings {
+		line := fmt.Sprintf("  %s: %s", finding.Severity, finding.Message)
+		if finding.Severity == severityError {
+			line = warningStyle.Render(line)
+		}
+		result.WriteString(line + "\n")
+	}
+	return result.String()
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
+)
+
+// minPageSize keeps pages usable on tiny terminals.
+const minPageSize = 5
+
+type pagerModel struct {
+	lines []string
+	size  int
+	page  int
+}
+
+func (m pagerModel) pages() int {
+	return (len(m.lines) + m.size - 1) / m.size
+}
+
+func (m pagerModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m pagerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+		case tea.WindowSizeMsg:
+			m.size = max(msg.Height-2, minPageSize)
+			m.page = min(m.page, m.pages()-1)
+		case tea.KeyMsg:
+			switch msg.String() {
+				case "n", "right", "pgdown", " ":
+					if m.page < m.pages()-1 {
+						m.page++
+					}
+				case "p", "left", "pgup", "b":
+					if m.page > 0 {
+						m.page--
+					}
+				case "enter":
+					if m.page == m.pages()-1 {
+						return m, tea.Quit
+					}
+					m.page++
+				case "q", "esc", "ctrl+c":
+					return m, tea.Quit
+			}
+	}
+	return m, nil
+}
+
+func (m pagerModel) View() string {
+	start := m.page * m.size
+	end := min(start+m.size, len(m.lines))
+	help := fmt.Sprintf("page %d/%d • n next • p previous • q done", m.page+1, m.pages())
+	return strings.Join(m.lines[start:end], "\n") + "\n" + menuHelpStyle.Render(help) + "\n"
+}
+
+// pageSize is how many lines fit on the terminal below a line of help.
+func pageSize() int {
+	_, height, err := term.GetSize(os.Stdout.Fd())
+	if err != nil {
+		return 0
+	}
+	return max(height-2, minPageSize)
+}
+
+// showPaged prints output a screen at a time when it doesn't fit on the
+// terminal. Output that isn't a terminal gets it all at once.
+func showPaged(output string) {
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	size := pageSize()
+	if size == 0 || len(lines) <= size || !term.IsTerminal(os.Stdin.Fd()) {
+		fmt.Println(output)
+		return
+	}
+	if plain {
+		showPagedPlain(lines, size)
+		return
+	}
+	if _, err := tea.NewProgram(pagerModel{lines: lines, size: size}).Run(); err != nil {
+		fmt.Println(output)
+	}
+}
+
+// showPagedPlain pages line by line for --plain, reading Enter between pages.
+func showPagedPlain(lines []string, size int) {
+	for start := 0; start < len(lines); start += size {
+		end := min(start+size, len(lines))
+		fmt.Println(strings.Join(lines[start:end], "\n"))
+		if end == len(lines) {
+			return
+		}
+		fmt.Printf("-- %d more lines, Enter to continue, q to stop --\n", len(lines)-end)
+		if answer, err := readLine(""); err != nil || strings.TrimSpace(answer) == "q" {
+			return
+		}
+	}
+}
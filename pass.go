@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+func passEntry(vpnName string) string {
+	return strings.TrimSpace(config.Pass[configName(vpnName)])
+}
+
+func passSecret(entry string) (string, error) {
+	if _, err := exec.LookPath("pass"); err != nil {
+		return "", fmt.Errorf("pass is not installed but a password-store entry is configured")
+	}
+	
+	output, err := executeCommandStdout("pass", "show", entry)
+	if err != nil {
+		return "", fmt.Errorf("could not read %s from the password store: %w", entry, err)
+	}
+	secret, _, _ := strings.Cut(output, "\n")
+	if secret == "" {
+		return "", fmt.Errorf("password-store entry %s is empty", entry)
+	}
+	return secret, nil
+}
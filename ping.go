@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var pingAverage = regexp.MustCompile(`= [\d.]+/([\d.]+)/`)
+
+func pingGateway(gateway string, count int) (time.Duration, error) {
+	if _, err := exec.LookPath("ping"); err != nil {
+		return 0, fmt.Errorf("ping is not installed")
+	}
+	
+	timeout := time.Duration(count)*2*time.Second + 2*time.Second
+	output, err := executeCommandTimeout(timeout, "ping", "-c", strconv.Itoa(count), "-W", "2", gateway)
+	if err != nil {
+		return 0, err
+	}
+	
+	match := pingAverage.FindStringSubmatch(output)
+	if match == nil {
+		return 0, fmt.Errorf("could not parse ping output")
+	}
+	ms, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(ms * float64(time.Millisecond)), nil
+}
+
+func vpnGateway(vpnName string) string {
+	output, err := executeCommand("nmcli", "-t", "connection", "show", vpnName)
+	if err != nil {
+		return ""
+	}
+	return parseConnectionShow(output).Gateway
+}
+
+func gatewayLatency(vpnName string) string {
+	gateway := vpnGateway(vpnName)
+	if gateway == "" {
+		return "Ping: no gateway reported for this connection"
+	}
+	
+	rtt, err := pingGateway(gateway, 3)
+	if err != nil {
+		return fmt.Sprintf("Ping: %s unreachable (%s)", gateway, strings.TrimSpace(strings.Split(err.Error(), "\n")[0]))
+	}
+	return fmt.Sprintf("Ping: %s avg %s", gateway, rtt.Round(100*time.Microsecond))
+}
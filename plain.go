@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/x/term"
+)
+
+// plainInput is the one reader every --plain prompt shares, so answers piped
+// on stdin aren't lost to a reader buffering ahead.
+var plainInput = bufio.NewReader(os.Stdin)
+
+// plainOutput gets the --plain prompts, keeping stdout for results.
+var plainOutput io.Writer = os.Stderr
+
+// readLine prints prompt and reads a line from plainInput. EOF before any
+// input aborts like Ctrl-C does in a form.
+func readLine(prompt string) (string, error) {
+	fmt.Fprint(plainOutput, prompt)
+	line, err := plainInput.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		fmt.Fprintln(plainOutput)
+		if errors.Is(err, io.EOF) {
+			return "", huh.ErrUserAborted
+		}
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// readSecret reads a line without echoing it when stdin is a terminal.
+func readSecret(prompt string) (string, error) {
+	if plainInput.Buffered() > 0 || !term.IsTerminal(os.Stdin.Fd()) {
+		return readLine(prompt)
+	}
+	fmt.Fprint(plainOutput, prompt)
+	secret, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(plainOutput)
+	if errors.Is(err, io.EOF) {
+		return "", huh.ErrUserAborted
+	}
+	return string(secret), err
+}
+
+// groupFields remembers the fields of each group made by newGroup, since huh
+// doesn't hand them back and --plain prompts for them itself.
+var groupFields = struct {
+	sync.Mutex
+	m map[*huh.Group][]huh.Field
+}{m: map[*huh.Group][]huh.Field{}}
+
+// newGroup is huh.NewGroup for forms made by newForm.
+func newGroup(fields ...huh.Field) *huh.Group {
+	group := huh.NewGroup(fields...)
+	groupFields.Lock()
+	groupFields.m[group] = fields
+	groupFields.Unlock()
+	return group
+}
+
+// Form is a huh form that asks line by line on stdin with --plain instead of
+// using huh's accessible mode, which echoes passwords and drops piped input.
+type Form struct {
+	*huh.Form
+	fields []huh.Field
+}
+
+// Run runs the form, or prompts for each field in turn with --plain.
+func (f *Form) Run() error {
+	if !plain {
+		return f.Form.Run()
+	}
+	for _, field := range f.fields {
+		if err := askPlain(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// askPlain prompts for field until its validation passes.
+func askPlain(field huh.Field) error {
+	v := reflect.ValueOf(field).Elem()
+	kind := v.Type().Name()
+	
+	if title := evalString(v, "title"); title != "" {
+		fmt.Fprintln(plainOutput, title)
+	}
+	if description := evalString(v, "description"); description != "" {
+		fmt.Fprintln(plainOutput, description)
+	}
+	for {
+		var err error
+		field.Focus()
+		switch {
+			case strings.HasPrefix(kind, "Select["):
+				err = askSelect(field, v)
+			case strings.HasPrefix(kind, "MultiSelect["):
+				err = askMultiSelect(field, v)
+			case kind == "Input":
+				err = askInput(field, v.FieldByName("textinput"))
+			case kind == "Text":
+				err = askText(field)
+			case kind == "Confirm":
+				err = askConfirm(field)
+			default:
+				return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := field.Error(); err != nil {
+			fmt.Fprintln(plainOutput, "Error:", err)
+			continue
+		}
+		fmt.Fprintln(plainOutput)
+		return nil
+	}
+}
+
+// evalString reads a static title or description off a huh field.
+func evalString(v reflect.Value, name string) string {
+	if f := v.FieldByName(name); f.IsValid() {
+		return f.FieldByName("val").String()
+	}
+	return ""
+}
+
+// optionKeys lists the labels of a select's options, and which are selected.
+func optionKeys(v reflect.Value) ([]string, []bool) {
+	options := v.FieldByName("options").FieldByName("val")
+	keys := make([]string, options.Len())
+	selected := make([]bool, options.Len())
+	for i := range keys {
+		keys[i] = options.Index(i).FieldByName("Key").String()
+		selected[i] = options.Index(i).FieldByName("selected").Bool()
+	}
+	return keys, selected
+}
+
+func printOptions(keys []string) {
+	for i, key := range keys {
+		fmt.Fprintf(plainOutput, "%d. %s\n", i+1, key)
+	}
+}
+
+// send feeds keys to a field the way a form would.
+func send(field huh.Field, keys ...tea.KeyMsg) {
+	for _, key := range keys {
+		field.Update(key)
+	}
+}
+
+// runes types s as a paste, so text like "tab" isn't taken for a key.
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s), Paste: true}
+}
+
+func askSelect(field huh.Field, v reflect.Value) error {
+	keys, _ := optionKeys(v)
+	if len(keys) == 0 {
+		return nil
+	}
+	if v.FieldByName("filtering").Bool() {
+		send(field, tea.KeyMsg{Type: tea.KeyEsc})
+	}
+	current := int(v.FieldByName("selected").Int()) + 1
+	printOptions(keys)
+	for {
+		answer, err := readLine(fmt.Sprintf("Choose [%d]: ", current))
+		if err != nil {
+			return err
+		}
+		choice := current
+		if answer = strings.TrimSpace(answer); answer != "" {
+			choice, err = strconv.Atoi(answer)
+			if err != nil || choice < 1 || choice > len(keys) {
+				fmt.Fprintf(plainOutput, "Enter a number from 1 to %d.\n", len(keys))
+				continue
+			}
+		}
+		send(field, tea.KeyMsg{Type: tea.KeyHome})
+		for range choice - 1 {
+			send(field, tea.KeyMsg{Type: tea.KeyDown})
+		}
+		send(field, tea.KeyMsg{Type: tea.KeyEnter})
+		return nil
+	}
+}
+
+func askMultiSelect(field huh.Field, v reflect.Value) error {
+	keys, selected := optionKeys(v)
+	if len(keys) == 0 {
+		return nil
+	}
+	if v.FieldByName("filtering").Bool() {
+		send(field, tea.KeyMsg{Type: tea.KeyEsc})
+	}
+	var current []string
+	for i, on := range selected {
+		if on {
+			current = append(current, strconv.Itoa(i+1))
+		}
+	}
+	printOptions(keys)
+	for {
+		answer, err := readLine(fmt.Sprintf("Choose, separated by spaces [%s]: ", strings.Join(current, " ")))
+		if err != nil {
+			return err
+		}
+		want := selected
+		if answer = strings.TrimSpace(answer); answer != "" {
+			want = make([]bool, len(keys))
+			valid := true
+			for _, word := range strings.FieldsFunc(answer, func(r rune) bool { return r == ' ' || r == ',' }) {
+				choice, err := strconv.Atoi(word)
+				if err != nil || choice < 1 || choice > len(keys) {
+					valid = false
+					break
+				}
+				want[choice-1] = true
+			}
+			if !valid {
+				fmt.Fprintf(plainOutput, "Enter numbers from 1 to %d.\n", len(keys))
+				continue
+			}
+		}
+		send(field, tea.KeyMsg{Type: tea.KeyHome})
+		for i := range keys {
+			if want[i] != selected[i] {
+				send(field, tea.KeyMsg{Type: tea.KeySpace})
+			}
+			send(field, tea.KeyMsg{Type: tea.KeyDown})
+		}
+		send(field, tea.KeyMsg{Type: tea.KeyEnter})
+		return nil
+	}
+}
+
+func askInput(field huh.Field, input reflect.Value) error {
+	current := input.FieldByName("value").Len() > 0
+	mode := textinput.EchoMode(input.FieldByName("EchoMode").Int())
+	var answer string
+	var err error
+	if mode != textinput.EchoNormal {
+		answer, err = readSecret("> ")
+	} else {
+		prompt := "> "
+		if current {
+			prompt = fmt.Sprintf("[%s] > ", field.GetValue())
+		}
+		answer, err = readLine(prompt)
+	}
+	if err != nil {
+		return err
+	}
+	if answer != "" || mode != textinput.EchoNormal {
+		send(field, tea.KeyMsg{Type: tea.KeyEnd}, tea.KeyMsg{Type: tea.KeyCtrlU})
+		if answer != "" {
+			send(field, runes(answer))
+		}
+	}
+	field.Blur()
+	return nil
+}
+
+func askText(field huh.Field) error {
+	answer, err := readLine("> ")
+	if err != nil {
+		return err
+	}
+	if answer != "" {
+		send(field, tea.KeyMsg{Type: tea.KeyCtrlEnd})
+		for range strings.Count(fmt.Sprint(field.GetValue()), "\n") + 1 {
+			send(field, tea.KeyMsg{Type: tea.KeyCtrlU}, tea.KeyMsg{Type: tea.KeyBackspace})
+		}
+		send(field, runes(answer))
+	}
+	field.Blur()
+	return nil
+}
+
+func askConfirm(field huh.Field) error {
+	current := "y/N"
+	if value, _ := field.GetValue().(bool); value {
+		current = "Y/n"
+	}
+	for {
+		answer, err := readLine(fmt.Sprintf("[%s] > ", current))
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "":
+			case "y", "yes":
+				send(field, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+			case "n", "no":
+				send(field, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+			default:
+				fmt.Fprintln(plainOutput, "Answer y or n.")
+				continue
+		}
+		field.Blur()
+		return nil
+	}
+}
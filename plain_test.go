@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/huh"
+)
+
+// usePlainInput runs forms in --plain mode reading answers from input.
+func usePlainInput(t *testing.T, input string) *strings.Builder {
+	t.Helper()
+	oldPlain, oldInput, oldOutput := plain, plainInput, plainOutput
+	output := &strings.Builder{}
+	plain, plainInput, plainOutput = true, bufio.NewReader(strings.NewReader(input)), output
+	t.Cleanup(func() {
+		plain, plainInput, plainOutput = oldPlain, oldInput, oldOutput
+	})
+	return output
+}
+
+func TestPlainFormsShareStdin(t *testing.T) {
+	output := usePlainInput(t, "2\n\nsecret\ny\n1 3\n")
+	var choice, name, password string
+	var sure bool
+	var picked []string
+	name = "wg0"
+	err := newForm(newGroup(
+		huh.NewSelect[string]().
+			Title("Pick a VPN").
+			Options(huh.NewOptions("Work", "Tokyo", "Home")...).
+			Filtering(true).
+			Value(&choice),
+		huh.NewInput().
+			Title("Name").
+			Value(&name),
+	)).Run()
+	if err != nil {
+		t.Fatalf("first form: %v", err)
+	}
+	err = newForm(
+		newGroup(huh.NewInput().
+			Title("Password").
+			EchoMode(huh.EchoModePassword).
+			Value(&password)),
+		newGroup(huh.NewConfirm().
+			Title("Sure?").
+			Value(&sure)),
+		newGroup(huh.NewMultiSelect[string]().
+			Options(huh.NewOptions("a", "b", "c")...).
+			Value(&picked)),
+	).Run()
+	if err != nil {
+		t.Fatalf("second form: %v", err)
+	}
+	
+	if choice != "Tokyo" || name != "wg0" || password != "secret" || !sure || strings.Join(picked, ",") != "a,c" {
+		t.Fatalf("answers = %q %q %q %v %q", choice, name, password, sure, picked)
+	}
+	if strings.Contains(output.String(), "secret") {
+		t.Fatalf("password was echoed:\n%s", output)
+	}
+	if !strings.Contains(output.String(), "2. Tokyo") {
+		t.Fatalf("options weren't listed:\n%s", output)
+	}
+}
+
+func TestPlainFormRepromptsOnInvalidAnswers(t *testing.T) {
+	usePlainInput(t, "9\n1\n\nok\n")
+	var choice, name string
+	err := newForm(newGroup(
+		huh.NewSelect[string]().
+			Options(huh.NewOptions("Work", "Home")...).
+			Value(&choice),
+		huh.NewInput().
+			Validate(func(s string) error {
+				if s == "" {
+					return errors.New("required")
+				}
+				return nil
+			}).
+			Value(&name),
+	)).Run()
+	if err != nil || choice != "Work" || name != "ok" {
+		t.Fatalf("got %q %q %v", choice, name, err)
+	}
+}
+
+func TestPlainFormAbortsAtEOF(t *testing.T) {
+	usePlainInput(t, "")
+	var sure bool
+	err := newForm(newGroup(huh.NewConfirm().Value(&sure))).Run()
+	if !errors.Is(err, huh.ErrUserAborted) {
+		t.Fatalf("err = %v, want huh.ErrUserAborted", err)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+func wireGuardVPNs() []string {
+	list := getVPNs()
+	var vpns []string
+	for i, id := range vpnIDs(list) {
+		if list[i].Type == "wireguard" {
+			vpns = append(vpns, id)
+		}
+	}
+	sortFavoritesFirst(vpns)
+	return vpns
+}
+
+// wireGuardConfig returns the wg-quick style config of a WireGuard
+// connection. NetworkManager doesn't export WireGuard peers, so for nmcli the
+// config is read from the running interface with wg showconf.
+func wireGuardConfig(vpnName string) (string, error) {
+	if !usingNmcli() {
+		path := filepath.Join(wgQuickDir, vpnName+".conf")
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrPermission) {
+			return executeCommand("sudo", "cat", path)
+		}
+		return string(data), err
+	}
+	
+	iface := vpnInterface(vpnName)
+	if iface == "" {
+		return "", fmt.Errorf("connect %s first, its peers are read from the running interface", vpnName)
+	}
+	output, err := executeCommand("sudo", "wg", "showconf", iface)
+	if err != nil {
+		return "", err
+	}
+	settings, err := executeCommand("nmcli", "-g", "ipv4.addresses,ipv4.dns", "connection", "show", vpnName)
+	if err != nil {
+		return "", err
+	}
+	
+	var extra []string
+	lines := outputLines(settings)
+	if len(lines) > 0 && lines[0] != "" {
+		extra = append(extra, "Address = "+lines[0])
+	}
+	if len(lines) > 1 && lines[1] != "" {
+		extra = append(extra, "DNS = "+lines[1])
+	}
+	return strings.Replace(output, "[Interface]\n", "[Interface]\n"+strings.Join(append(extra, ""), "\n"), 1), nil
+}
+
+// withoutPrivateKey drops the PrivateKey line so the config can be shared
+// without handing out the key.
+func withoutPrivateKey(config string) string {
+	var lines []string
+	for _, line := range strings.Split(config, "\n") {
+		key, _, _ := strings.Cut(line, "=")
+		if strings.EqualFold(strings.TrimSpace(key), "PrivateKey") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// printQRCode renders config as a QR code with half-height blocks, two
+// modules per character, in explicit black and white so it scans on light
+// and dark terminals alike.
+func printQRCode(config string) error {
+	code, err := qrcode.New(config, qrcode.Low)
+	if err != nil {
+		return fmt.Errorf("config does not fit in a QR code: %w", err)
+	}
+	
+	color := func(black bool) int {
+		if black {
+			return 16
+		}
+		return 231
+	}
+	bitmap := code.Bitmap()
+	var b strings.Builder
+	for y := 0; y < len(bitmap); y += 2 {
+		for x := range bitmap[y] {
+			bottom := false
+			if y+1 < len(bitmap) {
+				bottom = bitmap[y+1][x]
+			}
+			fmt.Fprintf(&b, "\x1b[38;5;%dm\x1b[48;5;%dm▀", color(bitmap[y][x]), color(bottom))
+		}
+		b.WriteString("\x1b[0m\n")
+	}
+	fmt.Print(b.String())
+	return nil
+}
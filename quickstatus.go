@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	quickBoxStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("63")).
+			Padding(0, 1)
+	quickTitleStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("42"))
+	quickIdleStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("245"))
+	quickLabelStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Width(9)
+)
+
+func quickStatus() string {
+	activeVpns := getActiveVPNs()
+	if len(activeVpns) == 0 {
+		return quickBoxStyle.Render(quickIdleStyle.Render("○ Not connected"))
+	}
+	
+	exitIP := "unknown"
+	if !config.ShowPublicIP {
+		exitIP = "not looked up (show_public_ip is off)"
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		if info, err := fetchPublicIP(ctx); err == nil {
+			exitIP = info.IP
+			if info.Country != "" {
+				exitIP = fmt.Sprintf("%s (%s)", info.IP, info.Country)
+			}
+		}
+	}
+	
+	row := func(label, value string) string {
+		return quickLabelStyle.Render(label) + value
+	}
+	
+	var sections []string
+	for _, vpn := range activeVpns {
+		throughput := "unknown"
+		if iface := vpnInterface(vpn); iface != "" {
+			if rx, tx, err := measureThroughput(iface, time.Second); err == nil {
+				throughput = fmt.Sprintf("↓ %s/s  ↑ %s/s", formatBytes(rx), formatBytes(tx))
+			}
+		}
+		sections = append(sections, strings.Join([]string{
+			quickTitleStyle.Render("● " + vpn),
+			row("Exit IP", exitIP),
+			row("Uptime", formatDuration(time.Since(connectedSince(vpn)))),
+			row("Traffic", throughput),
+		}, "\n"))
+	}
+	return quickBoxStyle.Render(strings.Join(sections, "\n\n"))
+}
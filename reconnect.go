@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	reconnectPollInterval = 5 * time.Second
+	reconnectMinBackoff   = 2 * time.Second
+	reconnectMaxBackoff   = 2 * time.Minute
+)
+
+// monitorAndReconnect keeps vpnName up until ctx is cancelled, reconnecting
+// with exponential backoff whenever it is no longer active.
+func monitorAndReconnect(ctx context.Context, vpnName string) {
+	backoff := reconnectMinBackoff
+	wait := time.Duration(0)
+	for {
+		select {
+			case <-ctx.Done():
+				logProgress("Stopped monitoring %s", vpnName)
+				return
+			case <-time.After(wait):
+		}
+		
+		if slices.Contains(getActiveVPNs(), vpnName) {
+			backoff = reconnectMinBackoff
+			wait = reconnectPollInterval
+			continue
+		}
+		
+		logProgress("%s is down, reconnecting", vpnName)
+		_, err := connectVPN(vpnName)
+		if err != nil {
+			logProgress("Reconnecting %s failed, retrying in %s\n%s", vpnName, backoff, err)
+			wait = backoff
+			backoff = min(backoff*2, reconnectMaxBackoff)
+			continue
+		}
+		
+		logProgress("Reconnected %s", vpnName)
+		if warning := eventHookWarning(EventReconnect, vpnName); warning != "" {
+			logProgress("%s", warning)
+		}
+		backoff = reconnectMinBackoff
+		wait = reconnectPollInterval
+	}
+}
+
+// reconnect bounces vpnName to recover a flaky tunnel, disconnecting and
+// connecting it again and printing each step. A kill switch protecting it is
+// turned back on, and on_reconnect runs once it is back up.
+func reconnect(vpnName string) error {
+	killSwitch := killSwitchProtects(vpnName)
+	fmt.Printf("Disconnecting %s...\n", vpnDisplayName(vpnName))
+	output, err := disconnectVPNByName(vpnName, true)
+	if err != nil {
+		return fmt.Errorf("could not disconnect %s: %w", vpnDisplayName(vpnName), err)
+	}
+	if output = strings.TrimSpace(output); output != "" {
+		fmt.Println(output)
+	}
+	
+	fmt.Printf("Connecting %s...\n", vpnDisplayName(vpnName))
+	output, err = connectInteractive(vpnName)
+	if output = strings.TrimSpace(output); output != "" {
+		fmt.Println(output)
+	}
+	if err != nil {
+		return err
+	}
+	if killSwitch {
+		output, err := connectKillSwitch(vpnName)
+		if err != nil {
+			return fmt.Errorf("%s is back up without its kill switch: %w", vpnDisplayName(vpnName), err)
+		}
+		fmt.Println(output)
+	}
+	if warning := eventHookWarning(EventReconnect, vpnName); warning != "" {
+		fmt.Print(warning)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+func startReminders() {
+	if config.Reminder.After <= 0 {
+		return
+	}
+	every := config.Reminder.Every
+	if every <= 0 {
+		every = time.Hour
+	}
+	
+	go func() {
+		lastReminded := map[string]time.Time{}
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		
+		for range ticker.C {
+			active := map[string]bool{}
+			for _, vpn := range getActiveVPNs() {
+				active[vpn] = true
+				uptime := time.Since(connectedSince(vpn))
+				if uptime < config.Reminder.After {
+					continue
+				}
+				if last, ok := lastReminded[vpn]; ok && time.Since(last) < every {
+					continue
+				}
+				notify("charmvpn", fmt.Sprintf("VPN %s has been up for %s", vpn, formatDuration(uptime)))
+				lastReminded[vpn] = time.Now()
+			}
+			
+			for vpn := range lastReminded {
+				if !active[vpn] {
+					delete(lastReminded, vpn)
+				}
+			}
+			pruneConnected(active)
+		}
+	}()
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// leftoverKillSwitch reports whether the kill switch table is still loaded,
+// for instance because the charmvpn that added it was killed.
+func leftoverKillSwitch() (bool, error) {
+	if !killSwitchAvailable() {
+		return false, nil
+	}
+	output, err := runSudo("nft", "list", "tables")
+	if err != nil {
+		return false, err
+	}
+	return strings.Contains(output, killSwitchTable), nil
+}
+
+// panicDisconnect restores sane networking after something went wrong: it
+// disconnects every VPN, removes the kill switch rules and, if confirmed,
+// restarts NetworkManager. Each step is reported as it happens and a failing
+// step doesn't stop the ones after it.
+func panicDisconnect() error {
+	var errs []error
+	
+	active := getActiveVPNs()
+	if len(active) == 0 {
+		fmt.Println("No active VPNs to disconnect")
+	}
+	for _, result := range disconnectEach(active) {
+		if result.Err != nil {
+			fmt.Printf("Failed to disconnect %s: %s\n", result.Name, firstLine(result.Err))
+			errs = append(errs, result.Err)
+		} else {
+			fmt.Printf("Disconnected %s\n", result.Name)
+		}
+	}
+	
+	removed := killSwitchOn()
+	if err := disableKillSwitch(true); err != nil {
+		fmt.Println("Failed to remove kill switch rules:", err)
+		errs = append(errs, err)
+		removed = false
+	}
+	leftover, err := leftoverKillSwitch()
+	switch {
+		case err != nil:
+			fmt.Println("Could not check for leftover kill switch rules:", firstLine(err))
+			errs = append(errs, err)
+		case leftover:
+			if _, err := runSudo("nft", "delete", "table", "inet", killSwitchTable); err != nil {
+				fmt.Println("Failed to remove kill switch rules:", firstLine(err))
+				errs = append(errs, err)
+			} else {
+				fmt.Println("Removed kill switch rules")
+			}
+		case removed:
+			fmt.Println("Removed kill switch rules")
+		default:
+			fmt.Println("No kill switch rules to remove")
+	}
+	
+	if usingNmcli() && term.IsTerminal(os.Stdin.Fd()) &&
+		confirm("Restart NetworkManager?", "This drops every network connection for a moment, including WiFi.") {
+		if _, err := runSudo("systemctl", "restart", "NetworkManager"); err != nil {
+			fmt.Println("Failed to restart NetworkManager:", firstLine(err))
+			errs = append(errs, err)
+		} else {
+			fmt.Println("Restarted NetworkManager")
+		}
+	}
+	invalidateCache()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const connectRetryDelay = 2 * time.Second
+
+// transientMessages are nmcli failures that tend to go away on a second try,
+// such as DHCP or DNS races while the tunnel comes up.
+var transientMessages = []string{
+	"ip configuration could not be reserved",
+	"ip configuration was unavailable",
+	"dhcp",
+	"temporary failure in name resolution",
+	"could not resolve",
+	"vpn service stopped unexpectedly",
+	"the vpn service returned invalid configuration",
+	"unknown error",
+}
+
+func isTransient(output string, err error) bool {
+	text := output
+	if err != nil {
+		text += "\n" + err.Error()
+	}
+	if authFailed(text) {
+		return false
+	}
+	lower := strings.ToLower(text)
+	for _, msg := range transientMessages {
+		if strings.Contains(lower, msg) {
+			return true
+		}
+	}
+	return false
+}
+
+func connectAttempts() int {
+	return max(config.ConnectAttempts, 1)
+}
+
+// retryConnect calls connect until it succeeds, fails for a reason that isn't
+// transient, or connect_attempts is used up. It returns the attempts made.
+func retryConnect(vpnName string, connect func() (string, error)) (output string, attempts int, err error) {
+	for attempts = 1; ; attempts++ {
+		output, err = connect()
+		if err == nil || attempts >= connectAttempts() || !isTransient(output, err) {
+			break
+		}
+		logVerbose("connecting %s failed (%v), retrying in %s", vpnName, err, connectRetryDelay)
+		select {
+			case <-baseContext().Done():
+				return output, attempts, err
+			case <-time.After(connectRetryDelay):
+		}
+	}
+	if err != nil && attempts > 1 {
+		err = fmt.Errorf("%w (after %d attempts)", err, attempts)
+	}
+	return output, attempts, err
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func validateInterval(value string) error {
+	interval, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return fmt.Errorf("enter a duration like 30m or 2h")
+	}
+	if interval < time.Minute {
+		return fmt.Errorf("interval must be at least 1m")
+	}
+	return nil
+}
+
+func logProgress(format string, args ...any) {
+	fmt.Printf("[%s] %s\n", time.Now().Format("15:04:05"), fmt.Sprintf(format, args...))
+}
+
+func rotateVPNs(ctx context.Context, vpns []string, interval time.Duration) {
+	current := ""
+	failures := 0
+	for i := 0; ctx.Err() == nil; i = (i + 1) % len(vpns) {
+		next := vpns[i]
+		if current != "" && current != next {
+			logProgress("Disconnecting %s", current)
+			disconnectVPNByName(current, true)
+			current = ""
+		}
+		
+		logProgress("Connecting %s", next)
+		if _, err := connectVPN(next); err != nil {
+			logProgress("Failed to connect %s, moving to the next server\n%s", next, err)
+			failures++
+			if failures < len(vpns) {
+				continue
+			}
+			logProgress("All servers failed, retrying in %s", interval)
+		} else {
+			current = next
+			logProgress("Connected to %s, next switch in %s", next, interval)
+		}
+		failures = 0
+		
+		select {
+			case <-ctx.Done():
+			case <-time.After(interval):
+		}
+	}
+	logProgress("Rotation stopped")
+}
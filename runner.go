@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+)
+
+// Runner runs an external command and returns its combined output. All
+// commands go through the package-level runner, so tests can swap in a fake
+// that returns canned nmcli output.
+type Runner interface {
+	Run(ctx context.Context, stdin string, name string, args ...string) (string, error)
+}
+
+type execRunner struct{}
+
+func (execRunner) Run(ctx context.Context, stdin string, name string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	output, err := cmd.CombinedOutput()
+	return string(output), err
+}
+
+// StdoutRunner is a Runner that can also return stdout and stderr apart, for
+// commands such as pass whose stdout is a secret and whose stderr may carry
+// gpg warnings.
+type StdoutRunner interface {
+	RunStdout(ctx context.Context, stdin string, name string, args ...string) (string, string, error)
+}
+
+func (execRunner) RunStdout(ctx context.Context, stdin string, name string, args ...string) (string, string, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	return string(output), stderr.String(), err
+}
+
+var runner Runner = execRunner{}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type fakeCommand struct {
+	stdin string
+	args  []string
+}
+
+// fakeRunner answers commands with canned output keyed by the command line.
+// Commands without an answer succeed with no output.
+type fakeRunner struct {
+	mu       sync.Mutex
+	outputs  map[string]string
+	commands []fakeCommand
+}
+
+func (r *fakeRunner) Run(ctx context.Context, stdin string, name string, args ...string) (string, error) {
+	command := append([]string{name}, args...)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.commands = append(r.commands, fakeCommand{stdin, command})
+	return r.outputs[strings.Join(command, " ")], nil
+}
+
+// useFakeRunner routes commands to a fakeRunner for the rest of the test,
+// with the nmcli backend, default config and empty config and state dirs.
+func useFakeRunner(t *testing.T, outputs map[string]string) *fakeRunner {
+	t.Helper()
+	fake := &fakeRunner{outputs: outputs}
+	savedRunner, savedBackend, savedConfig := runner, backend, config
+	t.Cleanup(func() {
+		runner, backend, config = savedRunner, savedBackend, savedConfig
+		invalidateCache()
+	})
+	runner, backend, config = fake, nmcliBackend{}, defaultConfig()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	invalidateCache()
+	return fake
+}
+
+func TestCommandStdoutLeavesStderrOut(t *testing.T) {
+	saved := runner
+	defer func() { runner = saved }()
+	runner = execRunner{}
+	
+	output, err := executeCommandStdout("sh", "-c", "echo secret; echo 'gpg: warning' >&2")
+	if err != nil || output != "secret\n" {
+		t.Fatalf("got %q, %v", output, err)
+	}
+	_, err = executeCommandStdout("sh", "-c", "echo partial; echo 'gpg: decryption failed' >&2; exit 2")
+	if err == nil || err.Error() != "gpg: decryption failed" {
+		t.Fatalf("err = %v, want the stderr of the command", err)
+	}
+}
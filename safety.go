@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type countdownTickMsg struct{}
+
+type countdownModel struct {
+	label     string
+	remaining int
+	aborted   bool
+}
+
+func countdownTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return countdownTickMsg{}
+	})
+}
+
+func (m countdownModel) Init() tea.Cmd {
+	return countdownTick()
+}
+
+func (m countdownModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg.(type) {
+		case tea.KeyMsg:
+			m.aborted = true
+			return m, tea.Quit
+		case countdownTickMsg:
+			m.remaining--
+			if m.remaining <= 0 {
+				return m, tea.Quit
+			}
+			return m, countdownTick()
+	}
+	return m, nil
+}
+
+func (m countdownModel) View() string {
+	if m.aborted {
+		return "Aborted\n"
+	}
+	if m.remaining <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s in %d... press any key to abort\n", m.label, m.remaining)
+}
+
+// safetyDelay gives the user a configurable countdown before a destructive
+// operation. It returns false if the user pressed a key, or Ctrl-C with
+// --plain, to abort.
+func safetyDelay(label string) bool {
+	if config.SafetyDelay <= 0 {
+		return true
+	}
+	
+	seconds := int(math.Ceil(config.SafetyDelay.Seconds()))
+	if plain {
+		return plainSafetyDelay(label, time.Duration(seconds)*time.Second)
+	}
+	final, err := tea.NewProgram(countdownModel{label: label, remaining: seconds}).Run()
+	if err != nil {
+		return false
+	}
+	return !final.(countdownModel).aborted
+}
+
+// plainSafetyDelay waits out delay for --plain, where keys only arrive with
+// Enter, so Ctrl-C aborts instead.
+func plainSafetyDelay(label string, delay time.Duration) bool {
+	ctx, stop := notifyInterrupt(os.Interrupt)
+	defer stop()
+	
+	fmt.Printf("%s in %s... press Ctrl-C to abort\n", label, delay)
+	select {
+		case <-ctx.Done():
+			fmt.Println("Aborted")
+			return false
+		case <-time.After(delay):
+			return true
+	}
+}
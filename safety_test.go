@@ -0,0 +1,30 @@
+package main
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestPlainSafetyDelayAbortsOnInterrupt(t *testing.T) {
+	go func() {
+		for !interruptHandled() {
+			time.Sleep(10 * time.Millisecond)
+		}
+		syscall.Kill(syscall.Getpid(), syscall.SIGINT)
+	}()
+	
+	start := time.Now()
+	if plainSafetyDelay("Disconnecting Work", time.Minute) {
+		t.Error("plainSafetyDelay() went ahead after Ctrl-C")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("plainSafetyDelay() took %s to abort", elapsed)
+	}
+}
+
+func TestPlainSafetyDelayGoesAhead(t *testing.T) {
+	if !plainSafetyDelay("Disconnecting Work", 10*time.Millisecond) {
+		t.Error("plainSafetyDelay() aborted without Ctrl-C")
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+)
+
+var secretsRequiredMessages = []string{
+	"secrets were required, but not provided",
+	"no valid vpn secrets",
+}
+
+func needsSecrets(output string) bool {
+	lower := strings.ToLower(output)
+	for _, msg := range secretsRequiredMessages {
+		if strings.Contains(lower, msg) {
+			return true
+		}
+	}
+	return false
+}
+
+func activateVPN(vpnName string, secret string, otp string, device string) (string, error) {
+	timeout := connectTimeout(vpnName)
+	logVerbose("connecting %s with a timeout of %s", vpnName, timeout)
+	args := []string{"connection", "up", vpnName}
+	if device != "" {
+		args = append(args, "ifname", device)
+	}
+	if secret == "" && otp == "" {
+		output, err := executeCommandTimeout(timeout, "nmcli", args...)
+		return output, parseConnectError(output, timeoutError(err, "connection", timeout))
+	}
+	
+	// nmcli reads the passwd-file from our stdin so the secret never touches disk.
+	passwd := otpSecrets(vpnName, secret, otp)
+	output, err := executeCommandInput(timeout, passwd, "nmcli", append(args, "passwd-file", "/dev/stdin")...)
+	return output, parseConnectError(output, timeoutError(err, "connection", timeout))
+}
+
+func promptSecret(vpnName string) (string, bool) {
+	var secret string
+	form := newForm(
+		newGroup(
+			huh.NewInput().
+				Title(fmt.Sprintf("Password for %s", vpnName)).
+				EchoMode(huh.EchoModePassword).
+				Value(&secret),
+		),
+	)
+	if err := form.Run(); err != nil || secret == "" {
+		return "", false
+	}
+	return secret, true
+}
+
+// saveVPNPassword stores secret in the NetworkManager profile. It goes in
+// through nmcli's editor on stdin, since arguments are visible in ps.
+func saveVPNPassword(vpnName string, secret string) (string, error) {
+	if strings.ContainsAny(secret, "\r\n") {
+		return "", fmt.Errorf("passwords with line breaks can only be saved in the keyring")
+	}
+	output, err := executeCommand("nmcli", "connection", "modify", vpnName, "+vpn.data", "password-flags=0")
+	if err != nil {
+		return output, err
+	}
+	commands := "set vpn.secrets password=" + secret + "\nsave persistent\nquit\n"
+	return executeCommandInput(DefaultCommandTimeout, commands, "nmcli", "connection", "edit", vpnName)
+}
+
+func connectInteractive(vpnName string) (string, error) {
+	return ignoreAlreadyConnected(connectInteractiveWith(vpnName, connectOptions{}))
+}
+
+// connectInteractiveWith connects vpnName with opts, asking for the password
+// when needed and offering to save it.
+func connectInteractiveWith(vpnName string, opts connectOptions) (string, error) {
+	var entered string
+	output, err := connectVPNWithSecret(vpnName, opts, func() (string, bool) {
+		secret, ok := promptSecret(vpnName)
+		entered = secret
+		return secret, ok
+	})
+	if entered == "" || err != nil {
+		return output, err
+	}
+	
+	save := "keyring"
+	saveForm := newForm(
+		newGroup(
+			huh.NewSelect[string]().
+				Title(fmt.Sprintf("Save the password for %s so you aren't asked again?", vpnName)).
+				Value(&save).
+				Options(
+					huh.NewOption("Save in the system keyring", "keyring"),
+					huh.NewOption("Save in the NetworkManager profile", "profile"),
+					huh.NewOption("Don't save", ""),
+				),
+		),
+	)
+	if err := saveForm.Run(); err != nil {
+		return output, nil
+	}
+	
+	switch save {
+		case "keyring":
+			err = saveSecret(vpnName, entered)
+		case "profile":
+			_, err = saveVPNPassword(vpnName, entered)
+		default:
+			return output, nil
+	}
+	if err != nil {
+		output += fmt.Sprintf("Could not save password: %s\n", err)
+	} else {
+		output += "Password saved\n"
+	}
+	return output, nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSaveVPNPasswordKeepsSecretOffArgv(t *testing.T) {
+	fake := useFakeRunner(t, nil)
+	
+	if _, err := saveVPNPassword("Work", "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+	sentOnStdin := false
+	for _, command := range fake.commands {
+		if slices.ContainsFunc(command.args, func(arg string) bool { return strings.Contains(arg, "hunter2") }) {
+			t.Errorf("secret passed as an argument: %q", command.args)
+		}
+		if strings.Contains(command.stdin, "set vpn.secrets password=hunter2\n") {
+			sentOnStdin = true
+		}
+	}
+	if !sentOnStdin {
+		t.Errorf("secret not sent to nmcli on stdin: %+v", fake.commands)
+	}
+}
+
+func TestSaveVPNPasswordRejectsLineBreaks(t *testing.T) {
+	fake := useFakeRunner(t, nil)
+	
+	if _, err := saveVPNPassword("Work", "hunter2\nquit"); err == nil {
+		t.Error("password with a line break was accepted")
+	}
+	if len(fake.commands) != 0 {
+		t.Errorf("ran %+v for a rejected password", fake.commands)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+)
+
+// defaultSocketPath is where --serve listens unless --socket says otherwise:
+// the user's runtime directory, or the state directory without one.
+func defaultSocketPath() (string, error) {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "charmvpn.sock"), nil
+	}
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "charmvpn.sock"), nil
+}
+
+// listenUnix listens on a socket only the current user can connect to,
+// replacing a stale socket left behind by an earlier run.
+func listenUnix(socketPath string) (net.Listener, error) {
+	if info, err := os.Lstat(socketPath); err == nil {
+		if info.Mode().Type() != fs.ModeSocket {
+			return nil, fmt.Errorf("%s exists and is not a socket", socketPath)
+		}
+		if conn, err := net.Dial("unix", socketPath); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("another charmvpn is already serving on %s", socketPath)
+		}
+		os.Remove(socketPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(socketPath), 0700); err != nil {
+		return nil, err
+	}
+	
+	umask := syscall.Umask(0177)
+	defer syscall.Umask(umask)
+	return net.Listen("unix", socketPath)
+}
+
+type disconnectJSON struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Output string `json:"output,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, code int, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	fmt.Fprintln(w, body)
+}
+
+func writeJSONError(w http.ResponseWriter, code int, err error) {
+	body, _ := marshalJSON(map[string]string{"error": err.Error()})
+	writeJSON(w, code, body)
+}
+
+// controlHandler serves the control API: GET /list and /status return the
+// same JSON as --list --json and --status --json, POST /connect?name=<name>
+// returns a ConnectResult and POST /disconnect[?name=<name>] the result per
+// VPN. Connects and disconnects run one at a time.
+func controlHandler() http.Handler {
+	var mu sync.Mutex
+	mux := http.NewServeMux()
+	
+	mux.HandleFunc("GET /list", func(w http.ResponseWriter, r *http.Request) {
+		body, err := listVPNsJSON()
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, body)
+	})
+	mux.HandleFunc("GET /status", func(w http.ResponseWriter, r *http.Request) {
+		body, err := statusJSON()
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, body)
+	})
+	mux.HandleFunc("POST /connect", func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			writeJSONError(w, http.StatusBadRequest, errors.New("missing name parameter"))
+			return
+		}
+		vpnName, err := resolveVPNName(name)
+		if err != nil {
+			writeJSONError(w, http.StatusNotFound, err)
+			return
+		}
+		mu.Lock()
+		result, _ := timedConnect(vpnName, connectVPN)
+		mu.Unlock()
+		
+		code := http.StatusOK
+		if !result.OK {
+			code = http.StatusBadGateway
+		}
+		body, err := marshalJSON(result)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, code, body)
+	})
+	mux.HandleFunc("POST /disconnect", func(w http.ResponseWriter, r *http.Request) {
+		vpns := getActiveVPNs()
+		if name := r.URL.Query().Get("name"); name != "" {
+			vpnName, err := resolveVPNName(name)
+			if err != nil {
+				writeJSONError(w, http.StatusNotFound, err)
+				return
+			}
+			vpns = []string{vpnName}
+		}
+		mu.Lock()
+		results := disconnectEach(vpns)
+		mu.Unlock()
+		
+		code := http.StatusOK
+		list := make([]disconnectJSON, len(results))
+		for i, result := range results {
+			list[i] = disconnectJSON{Name: vpnDisplayName(result.Name), OK: result.Err == nil, Output: result.Output}
+			if result.Err != nil {
+				list[i].Error = result.Err.Error()
+				code = http.StatusBadGateway
+			}
+		}
+		body, err := marshalJSON(list)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, code, body)
+	})
+	return mux
+}
+
+// serve runs the control API on a Unix socket until interrupted. The socket
+// is only accessible to the current user and nothing listens on the network.
+func serve(socketPath string) error {
+	listener, err := listenUnix(socketPath)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(socketPath)
+	
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	server := &http.Server{Handler: controlHandler()}
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background())
+	}()
+	
+	fmt.Fprintf(os.Stderr, "Serving on %s, press Ctrl-C to stop\n", socketPath)
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
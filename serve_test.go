@@ -0,0 +1,42 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDisconnectResolvesName(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		code  int
+		down  string
+	}{
+		{"exact name", "Work", http.StatusOK, "Work"},
+		{"unique substring", "tok", http.StatusOK, "Tokyo"},
+		{"unknown", "Paris", http.StatusNotFound, ""},
+		{"ambiguous", "o", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := useFakeRunner(t, map[string]string{
+				"nmcli -t -f NAME,UUID,TYPE,ACTIVE connection show": "Work:6b1c9e0e-0002-4a4e-9a5c-000000000002:vpn:yes\nTokyo:6b1c9e0e-0003-4a4e-9a5c-000000000003:wireguard:yes\n",
+			})
+			recorder := httptest.NewRecorder()
+			controlHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/disconnect?name="+tt.query, nil))
+			if recorder.Code != tt.code {
+				t.Errorf("POST /disconnect?name=%s = %d, want %d: %s", tt.query, recorder.Code, tt.code, recorder.Body)
+			}
+			var downed string
+			for _, command := range fake.commands {
+				if len(command.args) == 4 && command.args[1] == "connection" && command.args[2] == "down" {
+					downed = command.args[3]
+				}
+			}
+			if downed != tt.down {
+				t.Errorf("disconnected %q, want %q", downed, tt.down)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/x/term"
+)
+
+// setupTools are the programs charmvpn calls out to, checked by the setup
+// wizard so missing ones show up before a feature fails.
+var setupTools = []struct {
+	name    string
+	purpose string
+}{
+	{"nmcli", "manages VPNs through NetworkManager (needed by most features)"},
+	{"wg-quick", "manages WireGuard configs without NetworkManager"},
+	{"sudo", "exports configs with root-only secrets, kill switch, wg-quick"},
+	{"nft", "kill switch"},
+	{"notify-send", "desktop notifications"},
+}
+
+func configExists() bool {
+	path, err := configPath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return !errors.Is(err, fs.ErrNotExist)
+}
+
+func toolReport() string {
+	var report strings.Builder
+	for _, tool := range setupTools {
+		_, err := exec.LookPath(tool.name)
+		mark := "✓"
+		switch {
+			case plain && err == nil:
+				mark = "ok"
+			case plain:
+				mark = "missing"
+			case err != nil:
+				mark = "✗"
+		}
+		report.WriteString(fmt.Sprintf("  %-7s %-12s %s\n", mark, tool.name, tool.purpose))
+	}
+	return report.String()
+}
+
+// runSetup walks through the main settings and writes them to the config
+// file, starting from cfg. It returns the config that is in effect afterwards.
+func runSetup(cfg Config) (Config, error) {
+	fmt.Println("Checking for the tools charmvpn uses:")
+	fmt.Print(toolReport())
+	if _, err := exec.LookPath("nmcli"); err != nil {
+		fmt.Println(warningStyle.Render("Without nmcli only wg-quick can be used, for WireGuard VPNs."))
+	}
+	fmt.Println()
+	
+	edited := cfg
+	if edited.ExportDir == "" {
+		edited.ExportDir = "~"
+	}
+	if edited.Theme == "charm" {
+		edited.Theme = ""
+	}
+	form := newForm(
+		newGroup(
+			huh.NewSelect[string]().
+				Title("Backend").
+				Description("How charmvpn manages connections").
+				Value(&edited.Backend).
+				Options(
+					huh.NewOption("Automatic (nmcli if installed, otherwise wg-quick)", ""),
+					huh.NewOption("NetworkManager (nmcli)", "nmcli"),
+					huh.NewOption("WireGuard configs in /etc/wireguard (wg-quick)", "wg-quick"),
+				),
+		),
+		newGroup(
+			huh.NewInput().
+				Title("Export directory").
+				Description("Where Export VPN writes configs when no path is given").
+				Value(&edited.ExportDir),
+		),
+		newGroup(
+			huh.NewSelect[string]().
+				Title("Theme").
+				Value(&edited.Theme).
+				Options(
+					huh.NewOption("Charm", ""),
+					huh.NewOption("Dracula", "dracula"),
+					huh.NewOption("Catppuccin", "catppuccin"),
+					huh.NewOption("Base16", "base16"),
+					huh.NewOption("Base", "base"),
+				),
+		),
+	)
+	if err := form.Run(); err != nil {
+		return cfg, err
+	}
+	
+	if dir := strings.TrimSpace(edited.ExportDir); dir == "" || dir == "~" {
+		edited.ExportDir = ""
+	} else if expanded, err := expandHome(dir); err != nil {
+		return cfg, err
+	} else if err := ensureExportDir(expanded, confirm); err != nil {
+		fmt.Println(warningStyle.Render("Warning: " + err.Error()))
+	}
+	
+	path, _ := configPath()
+	if !confirm(fmt.Sprintf("Save these settings to %s?", path), "Run charmvpn --setup to change them later.") {
+		return cfg, nil
+	}
+	if err := saveConfig(edited); err != nil {
+		return cfg, fmt.Errorf("saving config: %w", err)
+	}
+	fmt.Println("Settings saved")
+	return edited, nil
+}
+
+// offerSetup runs the setup wizard on the first start from a terminal, when
+// there is no config file yet. Declining writes the defaults so the offer
+// isn't repeated.
+func offerSetup(cfg Config) Config {
+	if configExists() || !term.IsTerminal(os.Stdin.Fd()) {
+		return cfg
+	}
+	if !confirm("Welcome to charmvpn! Set it up now?", "Checks the tools it needs and asks for a few preferences. You can run charmvpn --setup later.") {
+		if err := saveConfig(cfg); err != nil {
+			logVerbose("saving default config: %v", err)
+		}
+		return cfg
+	}
+	updated, err := runSetup(cfg)
+	if err != nil && !errors.Is(err, huh.ErrUserAborted) {
+		printResult("", err)
+	}
+	return updated
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	latencyTarget = "1.1.1.1:443"
+	speedTestURL  = "https://speed.cloudflare.com/__down?bytes=10000000"
+)
+
+type SpeedResult struct {
+	Latency    time.Duration
+	Throughput float64
+}
+
+func measureLatency(ctx context.Context) (time.Duration, error) {
+	var dialer net.Dialer
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", latencyTarget)
+	if err != nil {
+		return 0, err
+	}
+	conn.Close()
+	return time.Since(start), nil
+}
+
+func measureDownload(ctx context.Context) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, speedTestURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected response from speed test: %s", resp.Status)
+	}
+	
+	n, err := io.Copy(io.Discard, resp.Body)
+	elapsed := time.Since(start).Seconds()
+	if n == 0 || elapsed == 0 {
+		return 0, err
+	}
+	// A cut-off download still tells us how fast it was going.
+	return float64(n) / elapsed, nil
+}
+
+func speedTest(ctx context.Context) (SpeedResult, error) {
+	var result SpeedResult
+	latency, err := measureLatency(ctx)
+	if err != nil {
+		return result, fmt.Errorf("latency check failed: %w", err)
+	}
+	result.Latency = latency
+	
+	throughput, err := measureDownload(ctx)
+	if err != nil {
+		return result, fmt.Errorf("download check failed: %w", err)
+	}
+	result.Throughput = throughput
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
+)
+
+type spinnerDone struct{}
+
+type spinnerModel struct {
+	spinner spinner.Model
+	title   string
+	done    bool
+}
+
+func (m spinnerModel) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+func (m spinnerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(spinnerDone); ok {
+		m.done = true
+		return m, tea.Quit
+	}
+	var cmd tea.Cmd
+	m.spinner, cmd = m.spinner.Update(msg)
+	return m, cmd
+}
+
+func (m spinnerModel) View() string {
+	if m.done {
+		return ""
+	}
+	return m.spinner.View() + " " + m.title
+}
+
+// withSpinner shows a spinner titled title while action runs and returns once
+// action has, even if the spinner failed. action must not prompt. The spinner
+// reads no keys and leaves signals alone, so Ctrl-C still reaches
+// interruptible. Without a terminal or with --plain action just runs.
+func withSpinner(title string, action func()) {
+	if plain || !term.IsTerminal(os.Stdout.Fd()) {
+		action()
+		return
+	}
+	
+	s := spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(dashboardTitleStyle))
+	program := tea.NewProgram(spinnerModel{spinner: s, title: title}, tea.WithInput(nil), tea.WithoutSignalHandler())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		action()
+		program.Send(spinnerDone{})
+	}()
+	if _, err := program.Run(); err != nil {
+		logVerbose("spinner: %v", err)
+	}
+	<-done
+}
+
+// spinningBackend shows a spinner while a connection attempt runs.
+type spinningBackend struct {
+	secretBackend
+}
+
+func (b spinningBackend) ConnectWithSecret(name string, secret string, otp string, device string) (output string, err error) {
+	withSpinner(fmt.Sprintf("Connecting %s...", name), func() {
+		output, err = b.secretBackend.ConnectWithSecret(name, secret, otp, device)
+	})
+	return output, err
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// splitTunnel reads the extra IPv4 routes of a connection, each in full with
+// any next hop, metric and attributes, and whether it keeps the default route
+// off the VPN.
+func splitTunnel(vpnName string) (routes []string, neverDefault bool, err error) {
+	output, err := executeCommand("nmcli", "-t", "-f", "ipv4.routes,ipv4.never-default", "connection", "show", vpnName)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, line := range outputLines(output) {
+		key, value, ok := terseProperty(line)
+		if !ok {
+			continue
+		}
+		switch key {
+			case "ipv4.routes":
+				for _, route := range strings.Split(value, ",") {
+					if route = strings.TrimSpace(route); route != "" {
+						routes = append(routes, route)
+					}
+				}
+			case "ipv4.never-default":
+				neverDefault = value == "yes"
+		}
+	}
+	return routes, neverDefault, nil
+}
+
+func validateCIDRList(value string) error {
+	for _, cidr := range splitServers(value) {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("%q is not a subnet like 10.0.0.0/8", cidr)
+		}
+	}
+	return nil
+}
+
+// setSplitTunnel sets the routes of a connection to routes, kept as
+// splitTunnel read them, plus a route through the VPN for each of cidrs not
+// already there. With onlyTheseThroughVPN the VPN no longer takes the default
+// route, so everything else bypasses it.
+func setSplitTunnel(vpnName string, routes []string, cidrs []string, onlyTheseThroughVPN bool) error {
+	routes = append([]string(nil), routes...)
+	for _, cidr := range cidrs {
+		_, ipnet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("%q is not a valid subnet", cidr)
+		}
+		if ipnet.IP.To4() == nil {
+			return fmt.Errorf("%s is not an IPv4 subnet", cidr)
+		}
+		if !hasRouteTo(routes, ipnet.String()) {
+			routes = append(routes, ipnet.String())
+		}
+	}
+	if onlyTheseThroughVPN && len(routes) == 0 {
+		return fmt.Errorf("add at least one subnet to route through the VPN")
+	}
+	
+	neverDefault := "no"
+	if onlyTheseThroughVPN {
+		neverDefault = "yes"
+	}
+	_, err := executeCommand("nmcli", "connection", "modify", vpnName,
+		"ipv4.routes", strings.Join(routes, ","),
+		"ipv4.never-default", neverDefault)
+	return err
+}
+
+// hasRouteTo reports whether one of routes goes to the subnet cidr.
+func hasRouteTo(routes []string, cidr string) bool {
+	for _, route := range routes {
+		if fields := strings.Fields(route); len(fields) > 0 && fields[0] == cidr {
+			return true
+		}
+	}
+	return false
+}
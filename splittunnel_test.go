@@ -0,0 +1,31 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSplitTunnelKeepsRouteAttributes(t *testing.T) {
+	fake := useFakeRunner(t, map[string]string{
+		"nmcli -t -f ipv4.routes,ipv4.never-default connection show Work": "ipv4.routes:10.0.0.0/8 10.8.0.1 100, 192.168.5.0/24 table=5\nipv4.never-default:yes\n",
+	})
+	
+	routes, onlyRoutes, err := splitTunnel("Work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"10.0.0.0/8 10.8.0.1 100", "192.168.5.0/24 table=5"}
+	if !slices.Equal(routes, want) || !onlyRoutes {
+		t.Fatalf("splitTunnel = %q, %v, want %q, true", routes, onlyRoutes, want)
+	}
+	
+	if err := setSplitTunnel("Work", routes, []string{"10.0.0.0/8", "172.16.0.0/12"}, true); err != nil {
+		t.Fatal(err)
+	}
+	last := strings.Join(fake.commands[len(fake.commands)-1].args, " ")
+	wantCommand := "nmcli connection modify Work ipv4.routes 10.0.0.0/8 10.8.0.1 100,192.168.5.0/24 table=5,172.16.0.0/12 ipv4.never-default yes"
+	if last != wantCommand {
+		t.Errorf("ran %q\nwant %q", last, wantCommand)
+	}
+}
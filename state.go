@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type State struct {
+	ConnectedAt   map[string]time.Time  `json:"connected_at"`
+	Usage         map[string]UsageStats `json:"usage"`
+	Countries     map[string]string     `json:"countries"`
+	LastConnected string                `json:"last_connected,omitempty"`
+}
+
+var stateMu sync.Mutex
+
+func stateDir() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "charmvpn"), nil
+}
+
+func statePath() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "state.json"), nil
+}
+
+func loadState() State {
+	state := State{
+		ConnectedAt: map[string]time.Time{},
+		Usage:       map[string]UsageStats{},
+		Countries:   map[string]string{},
+	}
+	path, err := statePath()
+	if err != nil {
+		return state
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return state
+	}
+	json.Unmarshal(data, &state)
+	if state.ConnectedAt == nil {
+		state.ConnectedAt = map[string]time.Time{}
+	}
+	if state.Usage == nil {
+		state.Usage = map[string]UsageStats{}
+	}
+	if state.Countries == nil {
+		state.Countries = map[string]string{}
+	}
+	return state
+}
+
+func saveState(state State) error {
+	path, err := statePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func updateState(fn func(*State)) {
+	if dryRun {
+		return
+	}
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	state := loadState()
+	fn(&state)
+	saveState(state)
+}
+
+func recordConnected(name string) {
+	updateState(func(s *State) {
+		s.ConnectedAt[name] = time.Now()
+		usage := s.Usage[name]
+		usage.LastRx, usage.LastTx = 0, 0
+		s.Usage[name] = usage
+	})
+}
+
+func forgetConnected(name string) {
+	updateState(func(s *State) {
+		delete(s.ConnectedAt, name)
+	})
+}
+
+func renameState(oldName, newName string) {
+	updateState(func(s *State) {
+		if since, ok := s.ConnectedAt[oldName]; ok {
+			s.ConnectedAt[newName] = since
+			delete(s.ConnectedAt, oldName)
+		}
+		if usage, ok := s.Usage[oldName]; ok {
+			s.Usage[newName] = usage
+			delete(s.Usage, oldName)
+		}
+		if country, ok := s.Countries[oldName]; ok {
+			s.Countries[newName] = country
+			delete(s.Countries, oldName)
+		}
+		if s.LastConnected == oldName {
+			s.LastConnected = newName
+		}
+	})
+}
+
+func pruneConnected(active map[string]bool) {
+	updateState(func(s *State) {
+		for name := range s.ConnectedAt {
+			if !active[name] {
+				delete(s.ConnectedAt, name)
+			}
+		}
+	})
+}
+
+// recordedConnectedAt returns when charmvpn saw name connect, or the zero
+// time when it didn't. Unlike connectedSince it never writes the state file.
+func recordedConnectedAt(name string) time.Time {
+	return loadState().ConnectedAt[name]
+}
+
+func connectedSince(name string) time.Time {
+	var since time.Time
+	updateState(func(s *State) {
+		since = s.ConnectedAt[name]
+		if since.IsZero() {
+			since = time.Now()
+			s.ConnectedAt[name] = since
+		}
+	})
+	return since
+}
+
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h := d / time.Hour
+	m := (d % time.Hour) / time.Minute
+	if h == 0 {
+		return fmt.Sprintf("%dm", m)
+	}
+	return fmt.Sprintf("%dh%02dm", h, m)
+}
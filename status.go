@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type VPNStatus struct {
+	Name    string    `json:"name"`
+	State   string    `json:"state"`
+	Device  string    `json:"device"`
+	IP4     string    `json:"ip4"`
+	Gateway string    `json:"gateway"`
+	Since   time.Time `json:"since,omitzero"`
+}
+
+// outputLines splits command output into lines, returning no lines at all for
+// blank output rather than a single empty one.
+func outputLines(output string) []string {
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return nil
+	}
+	return strings.Split(output, "\n")
+}
+
+// parseTerseLine splits a line of nmcli -t output into its fields. nmcli
+// escapes ':' and '\' inside values with a backslash, so names like
+// "IPv6\:Test" stay one field.
+func parseTerseLine(line string) []string {
+	var fields []string
+	var field strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+			case line[i] == '\\' && i+1 < len(line):
+				i++
+				field.WriteByte(line[i])
+			case line[i] == ':':
+				fields = append(fields, field.String())
+				field.Reset()
+			default:
+				field.WriteByte(line[i])
+		}
+	}
+	return append(fields, field.String())
+}
+
+// terseProperty splits a "key:value" line of nmcli -t connection show output.
+func terseProperty(line string) (key string, value string, ok bool) {
+	fields := parseTerseLine(strings.TrimSpace(line))
+	if len(fields) < 2 {
+		return "", "", false
+	}
+	return fields[0], strings.Join(fields[1:], ":"), true
+}
+
+// parseConnectionShow reads the status out of `nmcli -t connection show`.
+// Fields nmcli leaves out or reports as "--", as for connections that never
+// came up, stay empty.
+func parseConnectionShow(output string) VPNStatus {
+	var status VPNStatus
+	var devices string
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := terseProperty(line)
+		if !ok || value == "--" {
+			continue
+		}
+		
+		switch key {
+			case "connection.id":
+				status.Name = value
+			case "GENERAL.NAME":
+				if status.Name == "" {
+					status.Name = value
+				}
+			case "GENERAL.STATE":
+				status.State = value
+			case "GENERAL.IP-IFACE":
+				status.Device = value
+			case "GENERAL.DEVICES":
+				devices = value
+			case "IP4.ADDRESS[1]":
+				status.IP4 = value
+			case "IP4.GATEWAY":
+				status.Gateway = value
+			case "connection.timestamp":
+				if ts, err := strconv.ParseInt(value, 10, 64); err == nil && ts > 0 {
+					status.Since = time.Unix(ts, 0)
+				}
+		}
+	}
+	if status.Device == "" {
+		status.Device = devices
+	}
+	return status
+}
+
+// connectionUptime reports how long vpnName has been up, preferring the
+// connect time charmvpn recorded over NetworkManager's connection.timestamp.
+func connectionUptime(vpnName string) (time.Duration, error) {
+	if since, ok := loadState().ConnectedAt[vpnName]; ok {
+		return time.Since(since), nil
+	}
+	output, err := executeCommand("nmcli", "-g", "connection.timestamp", "connection", "show", vpnName)
+	if err != nil {
+		return 0, err
+	}
+	ts, err := strconv.ParseInt(strings.TrimSpace(output), 10, 64)
+	if err != nil || ts <= 0 {
+		return 0, fmt.Errorf("no activation time recorded for %s", vpnName)
+	}
+	return time.Since(time.Unix(ts, 0)), nil
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOutputLines(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{"empty", "", nil},
+		{"blank", " \n\n", nil},
+		{"one line", "a\n", []string{"a"}},
+		{"several lines", "a\nb\nc", []string{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := outputLines(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("outputLines(%q) = %q, want %q", tt.output, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVPNList(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []VPN
+	}{
+		{
+			name:   "no connections",
+			output: "",
+			want:   nil,
+		},
+		{
+			name: "only non-VPN connections",
+			output: "Wired connection 1:0c1f6a2e-1111-4a6b-9c55-000000000001:802-3-ethernet:yes\n" +
+				"Home WiFi:0c1f6a2e-2222-4a6b-9c55-000000000002:802-11-wireless:no\n" +
+				"lo:0c1f6a2e-3333-4a6b-9c55-000000000003:loopback:yes\n",
+			want: nil,
+		},
+		{
+			name: "VPNs mixed with other connections",
+			output: "Wired connection 1:0c1f6a2e-1111-4a6b-9c55-000000000001:802-3-ethernet:yes\n" +
+				"Work:0c1f6a2e-4444-4a6b-9c55-000000000004:vpn:yes\n" +
+				"Home WiFi:0c1f6a2e-2222-4a6b-9c55-000000000002:802-11-wireless:no\n" +
+				"wg0:0c1f6a2e-5555-4a6b-9c55-000000000005:wireguard:no\n",
+			want: []VPN{
+				{Name: "Work", UUID: "0c1f6a2e-4444-4a6b-9c55-000000000004", Type: "vpn", Active: true},
+				{Name: "wg0", UUID: "0c1f6a2e-5555-4a6b-9c55-000000000005", Type: "wireguard"},
+			},
+		},
+		{
+			name:   "truncated lines are skipped",
+			output: "Work:vpn\nTokyo:0c1f6a2e-6666-4a6b-9c55-000000000006:vpn:no\n",
+			want:   []VPN{{Name: "Tokyo", UUID: "0c1f6a2e-6666-4a6b-9c55-000000000006", Type: "vpn"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseVPNList(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseVPNList() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTerseLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want []string
+	}{
+		{"plain fields", "Work:vpn:yes", []string{"Work", "vpn", "yes"}},
+		{"escaped separator", `IPv6\:Test:vpn:no`, []string{"IPv6:Test", "vpn", "no"}},
+		{"escaped backslash", `C\\Users:vpn:no`, []string{`C\Users`, "vpn", "no"}},
+		{"empty fields", "Work::", []string{"Work", "", ""}},
+		{"trailing backslash", `Work\`, []string{`Work\`}},
+		{"IPv6 address", `IP6.ADDRESS[1]:fd00\:\:2/64`, []string{"IP6.ADDRESS[1]", "fd00::2/64"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseTerseLine(tt.line); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTerseLine(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConnectionShow(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   VPNStatus
+	}{
+		{
+			name:   "empty",
+			output: "",
+			want:   VPNStatus{},
+		},
+		{
+			name:   "minimal",
+			output: "GENERAL.NAME:Work\nGENERAL.STATE:activated\n",
+			want:   VPNStatus{Name: "Work", State: "activated"},
+		},
+		{
+			name:   "never connected",
+			output: "connection.id:Work\nconnection.timestamp:0\nGENERAL.STATE:--\nGENERAL.DEVICES:--\nIP4.GATEWAY:--\n",
+			want:   VPNStatus{Name: "Work"},
+		},
+		{
+			name: "full",
+			output: "connection.id:Work\n" +
+				"connection.timestamp:1760500000\n" +
+				"GENERAL.NAME:Work\n" +
+				"GENERAL.STATE:activated\n" +
+				"GENERAL.DEVICES:wlp2s0\n" +
+				"GENERAL.IP-IFACE:tun0\n" +
+				"IP4.ADDRESS[1]:10.8.0.2/24\n" +
+				"IP4.ADDRESS[2]:10.9.0.2/24\n" +
+				"IP4.GATEWAY:10.8.0.1\n" +
+				"IP6.ADDRESS[1]:fd00\\:\\:2/64\n",
+			want: VPNStatus{
+				Name:    "Work",
+				State:   "activated",
+				Device:  "tun0",
+				IP4:     "10.8.0.2/24",
+				Gateway: "10.8.0.1",
+				Since:   time.Unix(1760500000, 0),
+			},
+		},
+		{
+			name:   "device without IP interface",
+			output: "connection.id:Tokyo\nGENERAL.STATE:activating\nGENERAL.DEVICES:wg0\n",
+			want:   VPNStatus{Name: "Tokyo", State: "activating", Device: "wg0"},
+		},
+		{
+			name:   "name with a colon",
+			output: "connection.id:IPv6\\:Test\nGENERAL.NAME:ignored\n",
+			want:   VPNStatus{Name: "IPv6:Test"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseConnectionShow(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseConnectionShow() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	statusHeaderStyle  = lipgloss.NewStyle().Bold(true)
+	statusActiveStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	statusPendingStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	statusFailedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+)
+
+const statusColumnGap = 2
+
+// statusMissing fills cells of connections that report no device, address or
+// gateway, such as ones that are still activating.
+func statusMissing() string {
+	if plain {
+		return "-"
+	}
+	return "—"
+}
+
+func statusCell(value string) string {
+	if value == "" {
+		return statusMissing()
+	}
+	return value
+}
+
+func statusStateStyle(state string) lipgloss.Style {
+	switch strings.ToLower(state) {
+		case "activated", "connected":
+			return statusActiveStyle
+		case "activating", "deactivating":
+			return statusPendingStyle
+	}
+	return statusFailedStyle
+}
+
+// statusTable renders statuses as aligned columns with the state colored.
+// Widths are measured without escape codes, and lipgloss drops the colors
+// itself for NO_COLOR, --plain and output that isn't a terminal.
+func statusTable(statuses []VPNStatus, types map[string]string) string {
+	rows := [][]string{{"NAME", "TYPE", "STATE", "DEVICE", "IP", "GATEWAY", "UPTIME"}}
+	for _, status := range statuses {
+		uptime := statusMissing()
+		if d, err := connectionUptime(status.Name); err == nil {
+			uptime = formatDuration(d)
+		}
+		rows = append(rows, []string{vpnLabel(status.Name), vpnTypeName(types[status.Name]), statusCell(status.State), statusCell(status.Device), statusCell(status.IP4), statusCell(status.Gateway), uptime})
+	}
+	
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], lipgloss.Width(cell))
+		}
+	}
+	
+	var table strings.Builder
+	for r, row := range rows {
+		var line strings.Builder
+		for i, cell := range row {
+			styled := cell
+			switch {
+				case r == 0:
+					styled = statusHeaderStyle.Render(cell)
+				case i == 2:
+					styled = statusStateStyle(cell).Render(cell)
+			}
+			line.WriteString(styled)
+			if i < len(row)-1 {
+				line.WriteString(strings.Repeat(" ", widths[i]-lipgloss.Width(cell)+statusColumnGap))
+			}
+		}
+		table.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+	}
+	return table.String()
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// vpnPluginDirs hold the .name files NetworkManager VPN plugins install.
+var vpnPluginDirs = []string{
+	"/usr/lib/NetworkManager/VPN",
+	"/etc/NetworkManager/VPN",
+}
+
+// vpnPluginServices maps VPN types to the service the NetworkManager plugin
+// registers.
+var vpnPluginServices = map[string]string{
+	"openvpn":    "org.freedesktop.NetworkManager.openvpn",
+	"strongswan": "org.freedesktop.NetworkManager.strongswan",
+}
+
+func vpnPluginInstalled(vpnType string) bool {
+	service, ok := vpnPluginServices[vpnType]
+	if !ok {
+		return true
+	}
+	for _, dir := range vpnPluginDirs {
+		files, _ := filepath.Glob(filepath.Join(dir, "*.name"))
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err == nil && strings.Contains(string(data), "service="+service) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pluginWarning explains which package is missing when the NetworkManager
+// plugin for vpnType isn't installed.
+func pluginWarning(vpnType string) string {
+	if !usingNmcli() || vpnPluginInstalled(vpnType) {
+		return ""
+	}
+	return fmt.Sprintf("Warning: the NetworkManager %s plugin does not appear to be installed (usually network-manager-%s or NetworkManager-%s)",
+		vpnTypeName(vpnType), vpnType, vpnType)
+}
+
+// addIKEv2 creates a strongSwan IKEv2 connection authenticating with EAP.
+// The password is asked for when connecting. certificate is the CA or server
+// certificate and may be empty to trust the system CAs.
+func addIKEv2(name string, server string, user string, certificate string) (string, error) {
+	name, server, user = strings.TrimSpace(name), strings.TrimSpace(server), strings.TrimSpace(user)
+	if name == "" || server == "" || user == "" {
+		return "", errors.New("name, server and username are required")
+	}
+	
+	data := []string{
+		"address=" + server,
+		"method=eap",
+		"user=" + user,
+		"virtual=yes",
+		"encap=no",
+		"ipcomp=no",
+	}
+	if certificate = strings.TrimSpace(certificate); certificate != "" {
+		path, err := resolveImportPath(certificate)
+		if err != nil {
+			return "", err
+		}
+		data = append(data, "certificate="+path)
+	}
+	
+	output, err := executeCommand("nmcli", "connection", "add", "type", "vpn", "vpn-type", "strongswan",
+		"con-name", name, "vpn.data", strings.Join(data, ", "))
+	invalidateCache()
+	logActivity("import", name, err)
+	return output, err
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+type Route struct {
+	Net    *net.IPNet
+	Device string
+}
+
+func parseCIDRs(value string) []*net.IPNet {
+	var nets []*net.IPNet
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '{' || r == '}' || r == '=' || r == '\n'
+	})
+	for _, field := range fields {
+		if _, ipnet, err := net.ParseCIDR(field); err == nil {
+			nets = append(nets, ipnet)
+		}
+	}
+	return nets
+}
+
+func vpnSubnets(vpnName string) []*net.IPNet {
+	output, err := executeCommand("nmcli", "-g", "ipv4.addresses,ipv4.routes,ipv6.addresses,ipv6.routes", "connection", "show", vpnName)
+	if err != nil {
+		return nil
+	}
+	return parseCIDRs(output)
+}
+
+func currentRoutes() []Route {
+	var routes []Route
+	for _, family := range []string{"-4", "-6"} {
+		output, err := executeCommand("ip", family, "-o", "route", "show")
+		if err != nil {
+			continue
+		}
+		for _, line := range outputLines(output) {
+			fields := strings.Fields(line)
+			if len(fields) == 0 || fields[0] == "default" {
+				continue
+			}
+			dest := fields[0]
+			if !strings.Contains(dest, "/") {
+				if family == "-4" {
+					dest += "/32"
+				} else {
+					dest += "/128"
+				}
+			}
+			_, ipnet, err := net.ParseCIDR(dest)
+			if err != nil {
+				continue
+			}
+			route := Route{Net: ipnet}
+			for i := 0; i+1 < len(fields); i++ {
+				if fields[i] == "dev" {
+					route.Device = fields[i+1]
+				}
+			}
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+func subnetsOverlap(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+func isDefaultRoute(n *net.IPNet) bool {
+	ones, _ := n.Mask.Size()
+	return ones == 0
+}
+
+func findSubnetOverlaps(vpnName string) []string {
+	var overlaps []string
+	routes := currentRoutes()
+	for _, subnet := range vpnSubnets(vpnName) {
+		if isDefaultRoute(subnet) {
+			continue
+		}
+		for _, route := range routes {
+			if isDefaultRoute(route.Net) || !subnetsOverlap(subnet, route.Net) {
+				continue
+			}
+			device := route.Device
+			if device == "" {
+				device = "unknown device"
+			}
+			overlaps = append(overlaps, fmt.Sprintf("%s overlaps %s on %s", subnet, route.Net, device))
+		}
+	}
+	return overlaps
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+)
+
+var sudoAuthFailedMessages = []string{
+	"incorrect password",
+	"Sorry, try again",
+	"no password was provided",
+}
+
+// sudoNeedsPassword reports whether sudo would prompt, which would draw over
+// the forms on the terminal.
+func sudoNeedsPassword() bool {
+	_, err := executeCommand("sudo", "-n", "true")
+	return err != nil
+}
+
+func promptSudoPassword() (string, bool) {
+	var password string
+	form := newForm(
+		newGroup(
+			huh.NewInput().
+				Title("Password for sudo").
+				EchoMode(huh.EchoModePassword).
+				Value(&password),
+		),
+	)
+	if err := form.Run(); err != nil || password == "" {
+		return "", false
+	}
+	return password, true
+}
+
+// runSudoNoPrompt runs a command with sudo -n, failing instead of asking for
+// a password.
+func runSudoNoPrompt(args ...string) (string, error) {
+	return executeCommand("sudo", append([]string{"-n"}, args...)...)
+}
+
+// runSudo runs a command with sudo. When sudo needs a password it is asked
+// for in a form and handed to sudo -S on stdin, never echoed or kept.
+func runSudo(args ...string) (string, error) {
+	if !sudoNeedsPassword() {
+		return executeCommand("sudo", args...)
+	}
+	password, ok := promptSudoPassword()
+	if !ok {
+		return "", errors.New("sudo password required")
+	}
+	
+	sudoArgs := append([]string{"-S", "-p", ""}, args...)
+	output, err := executeCommandInput(DefaultCommandTimeout, password+"\n", "sudo", sudoArgs...)
+	if err != nil {
+		for _, msg := range sudoAuthFailedMessages {
+			if strings.Contains(output, msg) {
+				return "", fmt.Errorf("sudo authentication failed")
+			}
+		}
+	}
+	return output, err
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+)
+
+// untaggedTag selects the VPNs without any tag in the tag filter.
+const untaggedTag = "untagged"
+
+func vpnTags(vpnName string) []string {
+	return config.Tags[configName(vpnName)]
+}
+
+func hasTag(vpnName string, tag string) bool {
+	if tag == untaggedTag {
+		return len(vpnTags(vpnName)) == 0
+	}
+	for _, t := range vpnTags(vpnName) {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// allTags lists every tag in use, sorted.
+func allTags() []string {
+	seen := map[string]bool{}
+	var tags []string
+	for _, vpnTags := range config.Tags {
+		for _, tag := range vpnTags {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// filterByTag keeps the VPNs tagged tag, or the untagged ones for
+// untaggedTag. An empty tag keeps them all.
+func filterByTag(vpns []VPN, tag string) []VPN {
+	if tag == "" {
+		return vpns
+	}
+	var filtered []VPN
+	for _, vpn := range vpns {
+		if hasTag(vpn.Name, tag) {
+			filtered = append(filtered, vpn)
+		}
+	}
+	return filtered
+}
+
+// selectTag asks which tag to narrow a list down to. It returns "" for all
+// VPNs, and skips the question while no tags are configured.
+func selectTag() (string, bool) {
+	tags := allTags()
+	if len(tags) == 0 {
+		return "", true
+	}
+	
+	options := []huh.Option[string]{huh.NewOption("All VPNs", "")}
+	for _, tag := range tags {
+		options = append(options, huh.NewOption(tag, tag))
+	}
+	options = append(options, huh.NewOption("Untagged", untaggedTag))
+	
+	var tag string
+	form := newForm(
+		newGroup(
+			huh.NewSelect[string]().
+				Title("Filter by tag").
+				Options(options...).
+				Value(&tag),
+		),
+	)
+	if err := form.Run(); err != nil {
+		return "", false
+	}
+	return tag, true
+}
+
+func parseTags(value string) []string {
+	seen := map[string]bool{}
+	var tags []string
+	for _, tag := range strings.Split(value, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func validateTags(value string) error {
+	for _, tag := range parseTags(value) {
+		if tag == untaggedTag {
+			return fmt.Errorf("%q is reserved for VPNs without tags", untaggedTag)
+		}
+	}
+	return nil
+}
+
+func setTags(vpnName string, tags []string) error {
+	vpnName = configName(vpnName)
+	cfg := config
+	cfg.Tags = map[string][]string{}
+	for name, t := range config.Tags {
+		cfg.Tags[name] = t
+	}
+	if len(tags) == 0 {
+		delete(cfg.Tags, vpnName)
+	} else {
+		cfg.Tags[vpnName] = tags
+	}
+	if err := saveConfig(cfg); err != nil {
+		return fmt.Errorf("saving tags: %w", err)
+	}
+	config = cfg
+	return nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilterByTag(t *testing.T) {
+	saved := config
+	defer func() { config = saved }()
+	config.Tags = map[string][]string{"Work": {"clientA"}, "Tokyo": {"personal"}}
+	vpns := []VPN{{Name: "Work"}, {Name: "Tokyo"}, {Name: "Home"}}
+	
+	tests := []struct {
+		tag  string
+		want []VPN
+	}{
+		{"", vpns},
+		{"clientA", []VPN{{Name: "Work"}}},
+		{untaggedTag, []VPN{{Name: "Home"}}},
+		{"unused", nil},
+	}
+	for _, tt := range tests {
+		if got := filterByTag(vpns, tt.tag); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("filterByTag(%q) = %v, want %v", tt.tag, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TestReport describes a dry-run connection: whether the tunnel came up, the
+// gateway answered and traffic left through the VPN.
+type TestReport struct {
+	Name        string
+	ConnectTime time.Duration
+	Gateway     string
+	Latency     time.Duration
+	GatewayErr  error
+	PublicIP    IPInfo
+	PublicIPErr error
+	Err         error
+}
+
+func (r TestReport) Passed() bool {
+	return r.Err == nil && r.GatewayErr == nil && r.PublicIPErr == nil
+}
+
+// connectForTest brings vpnName up with its stored password but, unlike
+// connectVPN, runs no hooks, sends no notifications and records nothing, as
+// the test takes it down again straight away.
+func connectForTest(vpnName string) error {
+	secret, err := storedSecret(vpnName)
+	if err != nil {
+		return err
+	}
+	defer invalidateCache()
+	if sb, ok := backend.(secretBackend); ok {
+		_, err = sb.ConnectWithSecret(vpnName, secret, "", "")
+	} else {
+		_, err = backend.Connect(vpnName)
+	}
+	return err
+}
+
+// testVPN connects vpnName, checks the gateway and public IP, and disconnects
+// again. The returned error is set when the tunnel could not be brought up.
+func testVPN(vpnName string) (TestReport, error) {
+	report := TestReport{Name: vpnName}
+	start := time.Now()
+	if err := connectForTest(vpnName); err != nil {
+		report.Err = fmt.Errorf("connect failed: %w", err)
+		return report, report.Err
+	}
+	defer func() {
+		backend.Disconnect(vpnName)
+		invalidateCache()
+	}()
+	
+	if err := waitUntilActive(vpnName, connectTimeout(vpnName)); err != nil {
+		report.Err = err
+		return report, err
+	}
+	report.ConnectTime = time.Since(start)
+	
+	if report.Gateway = vpnGateway(vpnName); report.Gateway == "" {
+		report.GatewayErr = fmt.Errorf("no gateway reported")
+	} else {
+		report.Latency, report.GatewayErr = pingGateway(report.Gateway, 3)
+	}
+	report.PublicIP, report.PublicIPErr = lookupPublicIP()
+	return report, nil
+}
+
+func firstLine(err error) string {
+	return strings.TrimSpace(strings.Split(err.Error(), "\n")[0])
+}
+
+func formatTestReports(reports []TestReport) string {
+	var result strings.Builder
+	passed := 0
+	for _, r := range reports {
+		if r.Passed() {
+			passed++
+		}
+		status := "ok"
+		if !r.Passed() {
+			status = "FAILED"
+		}
+		result.WriteString(fmt.Sprintf("%s: %s\n", r.Name, status))
+		if r.Err != nil {
+			result.WriteString(fmt.Sprintf("  %s\n", firstLine(r.Err)))
+			continue
+		}
+		result.WriteString(fmt.Sprintf("  Connected in %s\n", r.ConnectTime.Round(100*time.Millisecond)))
+		if r.GatewayErr != nil {
+			result.WriteString(fmt.Sprintf("  Gateway %s unreachable (%s)\n", r.Gateway, firstLine(r.GatewayErr)))
+		} else {
+			result.WriteString(fmt.Sprintf("  Gateway %s avg %s\n", r.Gateway, r.Latency.Round(100*time.Microsecond)))
+		}
+		if r.PublicIPErr != nil {
+			result.WriteString(fmt.Sprintf("  Public IP lookup failed (%s)\n", firstLine(r.PublicIPErr)))
+		} else {
+			result.WriteString(fmt.Sprintf("  Public IP %s\n", r.PublicIP))
+		}
+	}
+	result.WriteString(fmt.Sprintf("%d of %d passed\n", passed, len(reports)))
+	return result.String()
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestConnectForTestLeavesNoTrace(t *testing.T) {
+	fake := useFakeRunner(t, nil)
+	config.Hooks = HooksConfig{PreConnect: "true", Connect: "true"}
+	
+	if err := connectForTest("Work"); err != nil {
+		t.Fatal(err)
+	}
+	for _, command := range fake.commands {
+		if command.args[0] != "nmcli" {
+			t.Errorf("ran %v", command.args)
+		}
+	}
+	path, _ := statePath()
+	if _, err := os.Stat(path); err == nil {
+		t.Error("connectForTest recorded the connection in the state file")
+	}
+}
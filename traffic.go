@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const trafficRefreshInterval = time.Second
+
+type trafficSample struct {
+	rx, tx uint64
+	err    error
+}
+
+type trafficModel struct {
+	vpn     string
+	iface   string
+	started bool
+	session UsageStats
+	rxRate  float64
+	txRate  float64
+	err     error
+}
+
+func (m trafficModel) sample() tea.Cmd {
+	return func() tea.Msg {
+		rx, tx, err := readInterfaceStats(m.iface)
+		return trafficSample{rx: rx, tx: tx, err: err}
+	}
+}
+
+func (m trafficModel) Init() tea.Cmd {
+	return m.sample()
+}
+
+func (m trafficModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+		case trafficSample:
+			if msg.err != nil {
+				m.err = msg.err
+				return m, nil
+			}
+			if m.started {
+				rx, tx := m.session.RxBytes, m.session.TxBytes
+				m.session.add(msg.rx, msg.tx)
+				seconds := trafficRefreshInterval.Seconds()
+				m.rxRate = float64(m.session.RxBytes-rx) / seconds
+				m.txRate = float64(m.session.TxBytes-tx) / seconds
+			} else {
+				m.started = true
+				m.session.LastRx, m.session.LastTx = msg.rx, msg.tx
+			}
+			return m, tea.Tick(trafficRefreshInterval, func(time.Time) tea.Msg {
+				return m.sample()()
+			})
+			
+		case tea.KeyMsg:
+			return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m trafficModel) View() string {
+	var b strings.Builder
+	b.WriteString(dashboardTitleStyle.Render(fmt.Sprintf("%s (%s)", vpnLabel(m.vpn), m.iface)) + "\n\n")
+	
+	if m.err != nil {
+		b.WriteString(errorStyle.Render(fmt.Sprintf("%s is gone; the tunnel went down", m.iface)) + "\n")
+	} else if !m.started {
+		b.WriteString("Reading counters...\n")
+	} else {
+		b.WriteString(fmt.Sprintf("↓ %s/s  (%s this session)\n", formatBytes(m.rxRate), formatBytes(float64(m.session.RxBytes))))
+		b.WriteString(fmt.Sprintf("↑ %s/s  (%s this session)\n", formatBytes(m.txRate), formatBytes(float64(m.session.TxBytes))))
+	}
+	
+	b.WriteString("\n" + dashboardHelpStyle.Render("press any key to go back") + "\n")
+	return b.String()
+}
+
+func runTrafficView(vpnName string) error {
+	iface := vpnInterface(vpnName)
+	if iface == "" {
+		return fmt.Errorf("no network device found for %s", vpnName)
+	}
+	_, err := tea.NewProgram(trafficModel{vpn: vpnName, iface: iface}).Run()
+	return err
+}
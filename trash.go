@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxTrashBackups is how many removed VPNs are kept for "Undo last remove".
+const maxTrashBackups = 10
+
+const trashTimeLayout = "20060102-150405.000000000"
+
+// trashDir holds a backup of each removed VPN in a directory of its own named
+// after the removal time, so the config keeps its file name and is imported
+// under its old name again.
+func trashDir() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "trash"), nil
+}
+
+// backupVPN exports vpnName into the trash before it is removed and returns
+// the path of the backup.
+func backupVPN(vpnName string) (string, error) {
+	if dryRunSkips("a backup of " + vpnName + " to the trash") {
+		return "", nil
+	}
+	vpnType, err := connectionVPNType(vpnName)
+	if err != nil {
+		return "", err
+	}
+	output, err := exportConfig(vpnName)
+	if err != nil {
+		return "", err
+	}
+	trash, err := trashDir()
+	if err != nil {
+		return "", err
+	}
+	
+	dir := filepath.Join(trash, time.Now().Format(trashTimeLayout))
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	name := strings.NewReplacer("/", "_", "\x00", "").Replace(vpnName)
+	path := filepath.Join(dir, defaultExportName(name, vpnType))
+	if err := os.WriteFile(path, []byte(output), 0600); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	pruneTrash(trash)
+	return path, nil
+}
+
+// trashBackups returns the backup directories in the trash, newest first.
+func trashBackups(trash string) ([]string, error) {
+	entries, err := os.ReadDir(trash)
+	if err != nil {
+		return nil, err
+	}
+	var dirs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dirs = append(dirs, filepath.Join(trash, entry.Name()))
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(dirs)))
+	return dirs, nil
+}
+
+func pruneTrash(trash string) {
+	dirs, err := trashBackups(trash)
+	if err != nil {
+		return
+	}
+	for i := maxTrashBackups; i < len(dirs); i++ {
+		if err := os.RemoveAll(dirs[i]); err != nil {
+			logVerbose("pruning %s: %v", dirs[i], err)
+		}
+	}
+}
+
+// lastBackup returns the config of the VPN removed most recently.
+func lastBackup() (string, error) {
+	trash, err := trashDir()
+	if err != nil {
+		return "", err
+	}
+	dirs, err := trashBackups(trash)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	for _, dir := range dirs {
+		files, _ := filepath.Glob(filepath.Join(dir, "*"))
+		if len(files) > 0 {
+			return files[0], nil
+		}
+	}
+	return "", fmt.Errorf("there is no removed VPN to restore")
+}
+
+// backupTime tells when the VPN backed up at path was removed.
+func backupTime(path string) string {
+	removed, err := time.ParseInLocation(trashTimeLayout, filepath.Base(filepath.Dir(path)), time.Local)
+	if err != nil {
+		return "an unknown date"
+	}
+	return removed.Format("2006-01-02 15:04")
+}
+
+// restoreFromBackup re-imports a config saved by backupVPN and drops it from
+// the trash once it is back. It refuses when a VPN of the same name has been
+// added since, rather than creating a duplicate.
+func restoreFromBackup(path string) error {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if exists, err := connectionExists(name); err == nil && exists {
+		return fmt.Errorf("a VPN named %s already exists", name)
+	}
+	if _, err := addVPN(path); err != nil {
+		return err
+	}
+	return os.RemoveAll(filepath.Dir(path))
+}
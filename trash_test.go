@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBackupVPNGoesToTrash(t *testing.T) {
+	useFakeRunner(t, map[string]string{
+		"nmcli -g connection.type,vpn.service-type connection show Work": "vpn\norg.freedesktop.NetworkManager.openvpn\n",
+		"nmcli connection export Work":                                   "client\nremote vpn.example.com\n",
+	})
+	
+	backup, err := backupVPN("Work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(backup) != "Work.ovpn" {
+		t.Errorf("backup = %s, want a Work.ovpn", backup)
+	}
+	if data, _ := os.ReadFile(backup); string(data) != "client\nremote vpn.example.com\n" {
+		t.Errorf("backup holds %q", data)
+	}
+	if last, err := lastBackup(); err != nil || last != backup {
+		t.Errorf("lastBackup = %q, %v, want %q", last, err, backup)
+	}
+}
+
+func TestPruneTrashKeepsNewest(t *testing.T) {
+	trash := t.TempDir()
+	for i := range maxTrashBackups + 2 {
+		if err := os.Mkdir(filepath.Join(trash, fmt.Sprintf("20240101-%02d", i)), 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pruneTrash(trash)
+	
+	dirs, err := trashBackups(trash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirs) != maxTrashBackups || filepath.Base(dirs[len(dirs)-1]) != "20240101-02" {
+		t.Errorf("kept %v", dirs)
+	}
+}
+
+func TestRemoveVPNAsksWhenBackupFails(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		confirm bool
+		deleted bool
+	}{
+		{"declined", false, false},
+		{"confirmed", true, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := useFakeRunner(t, nil)
+			asked := false
+			_, err := removeVPN("Work", func(title string, description string) bool {
+				asked = true
+				return tt.confirm
+			})
+			if !asked {
+				t.Fatal("removeVPN didn't ask after the backup failed")
+			}
+			if (err == nil) != tt.deleted {
+				t.Errorf("err = %v", err)
+			}
+			deleted := false
+			for _, command := range fake.commands {
+				deleted = deleted || strings.Join(command.args, " ") == "nmcli connection delete Work"
+			}
+			if deleted != tt.deleted {
+				t.Errorf("deleted = %v, want %v", deleted, tt.deleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/huh"
+)
+
+const usageSampleInterval = time.Minute
+
+type UsageStats struct {
+	RxBytes uint64    `json:"rx_bytes"`
+	TxBytes uint64    `json:"tx_bytes"`
+	LastRx  uint64    `json:"last_rx"`
+	LastTx  uint64    `json:"last_tx"`
+	Since   time.Time `json:"since"`
+}
+
+// add folds the current interface counters into the totals. Counters lower
+// than the last sample mean the tunnel interface was recreated, so the new
+// value is counted from zero.
+func (u *UsageStats) add(rx, tx uint64) {
+	if rx >= u.LastRx {
+		u.RxBytes += rx - u.LastRx
+	} else {
+		u.RxBytes += rx
+	}
+	if tx >= u.LastTx {
+		u.TxBytes += tx - u.LastTx
+	} else {
+		u.TxBytes += tx
+	}
+	u.LastRx, u.LastTx = rx, tx
+}
+
+func sampleUsage(vpnName string) {
+	iface := vpnInterface(vpnName)
+	if iface == "" {
+		return
+	}
+	rx, tx, err := readInterfaceStats(iface)
+	if err != nil {
+		return
+	}
+	
+	updateState(func(s *State) {
+		usage := s.Usage[vpnName]
+		if usage.Since.IsZero() {
+			usage.Since = time.Now()
+		}
+		usage.add(rx, tx)
+		s.Usage[vpnName] = usage
+	})
+}
+
+func endUsageSession(vpnName string) {
+	updateState(func(s *State) {
+		if usage, ok := s.Usage[vpnName]; ok {
+			usage.LastRx, usage.LastTx = 0, 0
+			s.Usage[vpnName] = usage
+		}
+	})
+}
+
+func startUsageSampler() {
+	go func() {
+		ticker := time.NewTicker(usageSampleInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			for _, vpn := range getActiveVPNs() {
+				sampleUsage(vpn)
+			}
+		}
+	}()
+}
+
+func usageReport() string {
+	for _, vpn := range getActiveVPNs() {
+		sampleUsage(vpn)
+	}
+	
+	usage := loadState().Usage
+	if len(usage) == 0 {
+		return "No usage recorded yet"
+	}
+	
+	names := make([]string, 0, len(usage))
+	for name := range usage {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	
+	var result strings.Builder
+	result.WriteString("VPN data usage:\n")
+	for _, name := range names {
+		u := usage[name]
+		result.WriteString(fmt.Sprintf("%s: ↓ %s  ↑ %s  (since %s)\n",
+			name, formatBytes(float64(u.RxBytes)), formatBytes(float64(u.TxBytes)), u.Since.Format("2006-01-02")))
+	}
+	return result.String()
+}
+
+func usageResetOptions() []huh.Option[string] {
+	options := []huh.Option[string]{
+		huh.NewOption("Keep counters", ""),
+		huh.NewOption("Reset all", "*"),
+	}
+	usage := loadState().Usage
+	names := make([]string, 0, len(usage))
+	for name := range usage {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		options = append(options, huh.NewOption("Reset "+name, name))
+	}
+	return options
+}
+
+func resetUsage(vpnName string) {
+	updateState(func(s *State) {
+		for name, usage := range s.Usage {
+			if vpnName != "" && name != vpnName {
+				continue
+			}
+			usage.RxBytes, usage.TxBytes = 0, 0
+			usage.Since = time.Now()
+			s.Usage[name] = usage
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const wgQuickDir = "/etc/wireguard"
+
+// wgQuickBackend manages WireGuard configs in dir with wg-quick, for systems
+// without NetworkManager. Both the directory and wg itself need root.
+type wgQuickBackend struct {
+	dir string
+}
+
+func (wgQuickBackend) Name() string {
+	return "wg-quick"
+}
+
+func (b wgQuickBackend) configNames() ([]string, error) {
+	var files []string
+	entries, err := os.ReadDir(b.dir)
+	switch {
+		case err == nil:
+			for _, entry := range entries {
+				files = append(files, entry.Name())
+			}
+		case errors.Is(err, fs.ErrPermission):
+			output, err := executeCommand("sudo", "ls", b.dir)
+			if err != nil {
+				return nil, err
+			}
+			files = strings.Fields(output)
+		case errors.Is(err, fs.ErrNotExist):
+			return nil, nil
+		default:
+			return nil, err
+	}
+	
+	var names []string
+	for _, file := range files {
+		if strings.HasSuffix(file, ".conf") {
+			names = append(names, strings.TrimSuffix(file, ".conf"))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (wgQuickBackend) activeInterfaces() (map[string]bool, error) {
+	output, err := executeCommand("sudo", "wg", "show", "interfaces")
+	if err != nil {
+		return nil, err
+	}
+	active := map[string]bool{}
+	for _, iface := range strings.Fields(output) {
+		active[iface] = true
+	}
+	return active, nil
+}
+
+func (b wgQuickBackend) List() ([]VPN, error) {
+	names, err := b.configNames()
+	if err != nil {
+		return nil, err
+	}
+	active, err := b.activeInterfaces()
+	if err != nil {
+		return nil, err
+	}
+	
+	vpns := make([]VPN, len(names))
+	for i, name := range names {
+		vpns[i] = VPN{Name: name, Type: "wireguard", Active: active[name]}
+	}
+	return vpns, nil
+}
+
+func (wgQuickBackend) Connect(name string) (string, error) {
+	timeout := connectTimeout(name)
+	output, err := executeCommandTimeout(timeout, "sudo", "wg-quick", "up", name)
+	return output, timeoutError(err, "connection", timeout)
+}
+
+func (wgQuickBackend) Disconnect(name string) (string, error) {
+	timeout := connectTimeout(name)
+	output, err := executeCommandTimeout(timeout, "sudo", "wg-quick", "down", name)
+	return output, timeoutError(err, "disconnect", timeout)
+}
+
+func (b wgQuickBackend) Status() ([]VPNStatus, error) {
+	active, err := b.activeInterfaces()
+	if err != nil {
+		return nil, err
+	}
+	
+	var statuses []VPNStatus
+	for iface := range active {
+		status := VPNStatus{Name: iface, State: "activated", Device: iface}
+		if output, err := executeCommand("ip", "-4", "-o", "addr", "show", "dev", iface); err == nil {
+			if fields := strings.Fields(output); len(fields) >= 4 {
+				status.IP4 = fields[3]
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses, nil
+}
+
+func (b wgQuickBackend) Import(path string) (string, error) {
+	if strings.ToLower(filepath.Ext(path)) != ".conf" {
+		return "", fmt.Errorf("wg-quick can only import WireGuard .conf files")
+	}
+	if vpnType, err := peekVPNType(path); err != nil {
+		return "", err
+	} else if vpnType != "wireguard" {
+		return "", fmt.Errorf("%s is not a WireGuard config", filepath.Base(path))
+	}
+	
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if err := validateWireGuardName(name); err != nil {
+		return "", err
+	}
+	dest := filepath.Join(b.dir, name+".conf")
+	if _, err := executeCommand("sudo", "install", "-m", "600", path, dest); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Installed %s as %s", filepath.Base(path), dest), nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+)
+
+// currentSSID returns the WiFi network the machine is connected to, or "" when
+// it isn't on WiFi. It reads NetworkManager's last scan rather than waiting
+// for a new one.
+func currentSSID() (string, error) {
+	output, err := executeCommand("nmcli", "-t", "-f", "active,ssid", "dev", "wifi", "list", "--rescan", "no")
+	if err != nil {
+		return "", err
+	}
+	for _, line := range outputLines(output) {
+		if active, ssid, ok := terseProperty(line); ok && active == "yes" {
+			return ssid, nil
+		}
+	}
+	return "", nil
+}
+
+// wifiVPN returns the current SSID and the VPN configured for it in wifi_vpns,
+// if that VPN is one of vpns. Both are "" off WiFi or on other networks.
+func wifiVPN(vpns []string) (string, string) {
+	if len(config.WiFiVPNs) == 0 || !usingNmcli() {
+		return "", ""
+	}
+	ssid, err := currentSSID()
+	if err != nil {
+		logVerbose("reading the WiFi network: %v", err)
+		return "", ""
+	}
+	vpn, ok := config.WiFiVPNs[ssid]
+	if !ok || !slices.Contains(vpns, vpn) {
+		return "", ""
+	}
+	return ssid, vpn
+}
+
+// wifiSuggestion is shown under the main menu when the WiFi network has a VPN
+// configured that isn't connected.
+func wifiSuggestion(active []string) string {
+	ssid, vpn := wifiVPN(getVPNList())
+	if vpn == "" || slices.Contains(active, vpn) {
+		return ""
+	}
+	return fmt.Sprintf("On %s: connecting %s is recommended", ssid, vpnDisplayName(vpn))
+}
+
+// autoconnectWiFiVPN connects the VPN configured for the WiFi network when
+// charmvpn starts, if autoconnect_on_wifi is set and it isn't up already. It
+// only runs at startup: switching networks later doesn't connect anything.
+func autoconnectWiFiVPN() {
+	if !config.AutoconnectOnWiFi {
+		return
+	}
+	ssid, vpn := wifiVPN(getVPNList())
+	if vpn == "" || slices.Contains(getActiveVPNs(), vpn) {
+		return
+	}
+	fmt.Printf("On %s, connecting %s\n", ssid, vpnDisplayName(vpn))
+	printResult(connectInteractive(vpn))
+}
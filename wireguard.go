@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var wgInterfaceName = regexp.MustCompile(`^[A-Za-z0-9_=+.-]{1,15}$`)
+
+func decodeWireGuardLink(link string) (string, error) {
+	link = strings.TrimSpace(link)
+	if len(link) >= 5 && strings.EqualFold(link[:5], "wg://") {
+		link = link[5:]
+	}
+	if strings.Contains(link, "[Interface]") {
+		return link, nil
+	}
+	
+	encodings := []*base64.Encoding{
+		base64.StdEncoding,
+		base64.URLEncoding,
+		base64.RawStdEncoding,
+		base64.RawURLEncoding,
+	}
+	for _, enc := range encodings {
+		decoded, err := enc.DecodeString(link)
+		if err == nil {
+			return string(decoded), nil
+		}
+	}
+	return "", errors.New("link is not a valid base64-encoded WireGuard config")
+}
+
+func validateWireGuardConfig(config string) error {
+	hasInterface, hasPeer := false, false
+	for _, line := range strings.Split(config, "\n") {
+		switch strings.TrimSpace(line) {
+			case "[Interface]":
+				hasInterface = true
+			case "[Peer]":
+				hasPeer = true
+		}
+	}
+	if !hasInterface {
+		return errors.New("config is missing an [Interface] section")
+	}
+	if !hasPeer {
+		return errors.New("config is missing a [Peer] section")
+	}
+	return nil
+}
+
+func validateWireGuardName(name string) error {
+	if !wgInterfaceName.MatchString(name) {
+		return errors.New("name must be 1-15 characters of letters, digits or _=+.-")
+	}
+	return nil
+}
+
+func importWireGuardLink(link string, name string) (string, error) {
+	config, err := decodeWireGuardLink(link)
+	if err != nil {
+		return "", err
+	}
+	if err := validateWireGuardConfig(config); err != nil {
+		return "", err
+	}
+	if err := validateWireGuardName(name); err != nil {
+		return "", err
+	}
+	
+	dir, err := os.MkdirTemp("", "charmvpn-wg-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+	
+	path := filepath.Join(dir, name+".conf")
+	if err := os.WriteFile(path, []byte(config), 0600); err != nil {
+		return "", fmt.Errorf("writing temporary config: %w", err)
+	}
+	defer invalidateCache()
+	return backend.Import(path)
+}